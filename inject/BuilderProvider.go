@@ -1,12 +1,20 @@
 package inject
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 type BuilderProvider struct {
 	Name           string
 	Context        reflect.Type
 	Builder        interface{}
 	ResolveContext Graph
+	As             []reflect.Type
+}
+
+func (p BuilderProvider) GetAs() []reflect.Type {
+	return p.As
 }
 
 func (p BuilderProvider) GetContext() reflect.Type {
@@ -34,8 +42,15 @@ func (p BuilderProvider) Resolve() interface{} {
 	args := make([]reflect.Value, typeInfo.NumIn())
 	for i := 0; i < typeInfo.NumIn(); i++ {
 		argTypeInfo := typeInfo.In(i)
-		switch argTypeInfo.Kind() {
-		case reflect.Interface:
+		switch {
+		case isParamObject(argTypeInfo):
+			if arg, err := p.resolveParamObject(argTypeInfo); err == nil {
+				args[i] = arg
+			} else {
+				return nil
+			}
+
+		case argTypeInfo.Kind() == reflect.Interface || argTypeInfo.Kind() == reflect.Ptr:
 			if found, err := p.ResolveContext.Find(argTypeInfo, nil, ""); err == nil {
 				args[i] = reflect.ValueOf(found)
 			} else {
@@ -55,3 +70,36 @@ func (p BuilderProvider) Resolve() interface{} {
 	v := reflect.ValueOf(p.Builder).Call(args)
 	return v[0].Interface()
 }
+
+// resolveParamObject builds a parameter object (a struct embedding In) by
+// finding each inject-tagged field individually. The parameter object's type
+// is used as the context, as it would be for struct injection. Optional
+// fields for which the graph has nothing to offer are left at their zero
+// value; ambiguous ones are still an error.
+func (p BuilderProvider) resolveParamObject(typeInfo reflect.Type) (reflect.Value, error) {
+	v := reflect.New(typeInfo).Elem()
+	for i := 0; i < typeInfo.NumField(); i++ {
+		fieldInfo := typeInfo.Field(i)
+		if _, ok := structTagLookup(fieldInfo.Tag, injectTag); !ok {
+			continue
+		}
+
+		field := v.Field(i)
+		options := parseInjectTag(fieldInfo.Tag.Get(injectTag))
+
+		if !field.CanSet() {
+			return v, fmt.Errorf("Cannot set a field (%s) on a parameter object (%s).", fieldInfo.Name, typeInfo)
+		}
+
+		found, err := p.ResolveContext.Find(field.Type(), typeInfo, options.name)
+		switch {
+		case err == nil:
+			field.Set(reflect.ValueOf(found))
+
+		case !options.optional || p.ResolveContext.Provides(field.Type(), typeInfo, options.name):
+			return v, err
+		}
+	}
+
+	return v, nil
+}
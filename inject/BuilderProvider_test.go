@@ -115,7 +115,7 @@ var _ = Describe("BuilderProvider", func() {
 					for i := 0; i < typeInfo.NumIn(); i++ {
 						argTypeInfo := typeInfo.In(i)
 						switch argTypeInfo.Kind() {
-						case Interface:
+						case Interface, Ptr:
 							calls = append(calls,
 								mockGraph.EXPECT().Find(argTypeInfo, nil, "").Return(mockResponseForArgs[i]...),
 							)
@@ -148,9 +148,92 @@ var _ = Describe("BuilderProvider", func() {
 				Entry("Interface arg with error", false, func(_ interface{}) *Struct {
 					return &Struct{}
 				}, []interface{}{nil, errors.New("Encountered error")}),
+				Entry("Pointer args", true, func(s *Struct) *Struct {
+					return s
+				}, []interface{}{&Struct{}, nil}),
 			)
 		})
 
+		Context("Given a builder with a parameter object", func() {
+			type Params struct {
+				In
+
+				Url     string     `inject:"helloservice.url"`
+				Port    int        `inject:"helloservice.port,optional"`
+				Service InterfaceA `inject:""`
+			}
+
+			var paramsType Type
+
+			BeforeEach(func() {
+				paramsType = TypeOf(Params{})
+				p.Builder = func(params Params) *Params {
+					return &params
+				}
+			})
+
+			Context("With every field available", func() {
+				BeforeEach(func() {
+					calls = append(calls,
+						mockGraph.EXPECT().Find(TypeOf(""), paramsType, "helloservice.url").Return("https://www.example.org", nil),
+						mockGraph.EXPECT().Find(TypeOf(0), paramsType, "helloservice.port").Return(8080, nil),
+						mockGraph.EXPECT().Find(TypeOf((*InterfaceA)(nil)).Elem(), paramsType, "").Return(&PtrDecorated{}, nil),
+					)
+				})
+
+				It("Finds each field by name and context", func() {
+					v := p.Resolve()
+					Expect(v).ToNot(BeNil())
+					Expect(v.(*Params).Url).To(Equal("https://www.example.org"))
+					Expect(v.(*Params).Port).To(Equal(8080))
+					Expect(v.(*Params).Service).To(Equal(&PtrDecorated{}))
+				})
+			})
+
+			Context("With a missing optional field", func() {
+				BeforeEach(func() {
+					calls = append(calls,
+						mockGraph.EXPECT().Find(TypeOf(""), paramsType, "helloservice.url").Return("https://www.example.org", nil),
+						mockGraph.EXPECT().Find(TypeOf(0), paramsType, "helloservice.port").Return(nil, errors.New("Encountered error")),
+						mockGraph.EXPECT().Provides(TypeOf(0), paramsType, "helloservice.port").Return(false),
+						mockGraph.EXPECT().Find(TypeOf((*InterfaceA)(nil)).Elem(), paramsType, "").Return(&PtrDecorated{}, nil),
+					)
+				})
+
+				It("Leaves the field at its zero value", func() {
+					v := p.Resolve()
+					Expect(v).ToNot(BeNil())
+					Expect(v.(*Params).Port).To(BeZero())
+				})
+			})
+
+			Context("With an ambiguous optional field", func() {
+				BeforeEach(func() {
+					calls = append(calls,
+						mockGraph.EXPECT().Find(TypeOf(""), paramsType, "helloservice.url").Return("https://www.example.org", nil),
+						mockGraph.EXPECT().Find(TypeOf(0), paramsType, "helloservice.port").Return(nil, errors.New("Found multiple providers")),
+						mockGraph.EXPECT().Provides(TypeOf(0), paramsType, "helloservice.port").Return(true),
+					)
+				})
+
+				It("Returns nil", func() {
+					Expect(p.Resolve()).To(BeNil())
+				})
+			})
+
+			Context("With a missing required field", func() {
+				BeforeEach(func() {
+					calls = append(calls,
+						mockGraph.EXPECT().Find(TypeOf(""), paramsType, "helloservice.url").Return(nil, errors.New("Encountered error")),
+					)
+				})
+
+				It("Returns nil", func() {
+					Expect(p.Resolve()).To(BeNil())
+				})
+			})
+		})
+
 		DescribeTable("Given a non-func as a builder",
 			func(v interface{}) {
 				p.Builder = v
@@ -0,0 +1,127 @@
+package inject
+
+import (
+	"os"
+	"reflect"
+)
+
+// ConditionState is what a Condition is evaluated against.
+type ConditionState struct {
+	// Profiles are the graph's active profiles.
+	Profiles []string
+
+	// Active are the active providers registered before the one whose
+	// conditions are being evaluated.
+	Active []Provider
+}
+
+// Condition reports whether a conditional provider is active.
+type Condition func(state ConditionState) bool
+
+// ConditionalProvider wraps a provider which is only active when all of its
+// conditions hold. Conditions are evaluated when the graph is resolved, or
+// when it is first used, in the order providers were registered.
+type ConditionalProvider struct {
+	Provider
+	When []Condition
+}
+
+func (p ConditionalProvider) GetAs() []reflect.Type {
+	if asProvider, ok := p.Provider.(AsProvider); ok {
+		return asProvider.GetAs()
+	}
+
+	return nil
+}
+
+func (p ConditionalProvider) GetConditions() []Condition {
+	return p.When
+}
+
+// OnProfile holds when any of the given profiles is active.
+func OnProfile(profiles ...string) Condition {
+	return func(state ConditionState) bool {
+		for _, active := range state.Profiles {
+			for _, profile := range profiles {
+				if active == profile {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+// OnEnv holds when the environment variable is set.
+func OnEnv(variable string) Condition {
+	return func(ConditionState) bool {
+		_, ok := os.LookupEnv(variable)
+		return ok
+	}
+}
+
+// OnBinding holds when an active provider registered earlier satisfies the
+// given type and name.
+func OnBinding(typeInfo reflect.Type, name string) Condition {
+	return func(state ConditionState) bool {
+		return len(selectProvidersByName(selectProvidersByType(state.Active, typeInfo), name)) > 0
+	}
+}
+
+// OnMissingBinding holds when no active provider registered earlier
+// satisfies the given type and name.
+func OnMissingBinding(typeInfo reflect.Type, name string) Condition {
+	return Negate(OnBinding(typeInfo, name))
+}
+
+// Negate holds when condition does not.
+func Negate(condition Condition) Condition {
+	return func(state ConditionState) bool {
+		return !condition(state)
+	}
+}
+
+// WithProfiles sets the active profiles of a graph.
+func WithProfiles(profiles ...string) Option {
+	return func(g *graph) {
+		g.profiles = append(g.profiles, profiles...)
+	}
+}
+
+type conditionalProvider interface {
+	GetConditions() []Condition
+}
+
+// active returns the providers whose conditions hold.
+func (g *graph) active() []Provider {
+	if g.activeProviders != nil {
+		return g.activeProviders
+	}
+
+	active := []Provider{}
+	for _, provider := range g.providers {
+		if conditional, ok := provider.(conditionalProvider); ok &&
+			!conditionsHold(conditional.GetConditions(), ConditionState{
+				Profiles: g.profiles,
+				Active:   active,
+			}) {
+			continue
+		}
+
+		active = append(active, provider)
+	}
+
+	g.activeProviders = active
+	return active
+}
+
+func conditionsHold(conditions []Condition, state ConditionState) bool {
+	for _, condition := range conditions {
+		if !condition(state) {
+			return false
+		}
+	}
+
+	return true
+}
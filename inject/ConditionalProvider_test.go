@@ -0,0 +1,185 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/impinj/go-inject/inject"
+	"os"
+	"reflect"
+)
+
+var _ = Describe("ConditionalProvider", func() {
+	var (
+		dev, prod CustomA
+		g         Graph
+	)
+
+	interfaceA := reflect.TypeOf((*InterfaceA)(nil)).Elem()
+
+	providers := func() []Provider {
+		return []Provider{
+			&ConditionalProvider{
+				Provider: &ValueProvider{Value: &dev},
+				When:     []Condition{OnProfile("dev", "test")},
+			},
+			&ConditionalProvider{
+				Provider: &ValueProvider{Value: &prod},
+				When:     []Condition{OnProfile("prod")},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		dev = CustomA{Val: 1}
+		prod = CustomA{Val: 2}
+	})
+
+	DescribeTable("Selects providers by profile",
+		func(profiles []string, expected *CustomA) {
+			g = NewGraph(WithProfiles(profiles...))
+			g.Provide(providers()...)
+			Expect(g.Resolve()).To(Succeed())
+
+			v, err := g.Find(interfaceA, nil, "")
+			if expected == nil {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(v).To(BeIdenticalTo(expected))
+		},
+		Entry("Dev", []string{"dev"}, &dev),
+		Entry("Test", []string{"test"}, &dev),
+		Entry("Prod", []string{"prod"}, &prod),
+		Entry("None", nil, nil),
+	)
+
+	It("Is evaluated on first use without Resolve", func() {
+		g = NewGraph(WithProfiles("prod"))
+		g.Provide(providers()...)
+
+		Expect(g.Find(interfaceA, nil, "")).To(BeIdenticalTo(&prod))
+	})
+
+	Describe("Conditions", func() {
+		var state ConditionState
+
+		BeforeEach(func() {
+			state = ConditionState{
+				Profiles: []string{"dev"},
+				Active: []Provider{
+					&ValueProvider{Name: "helloservice.url", Value: "https://www.example.org"},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("INJECT_TEST_CONDITION")
+		})
+
+		DescribeTable("Evaluate against the state",
+			func(condition Condition, expected bool) {
+				Expect(condition(state)).To(Equal(expected))
+			},
+			Entry("Active profile", OnProfile("prod", "dev"), true),
+			Entry("Inactive profile", OnProfile("prod"), false),
+			Entry("Negate", Negate(OnProfile("prod")), true),
+			Entry("Present binding", OnBinding(reflect.TypeOf(""), "helloservice.url"), true),
+			Entry("Binding with another name", OnBinding(reflect.TypeOf(""), "helloservice.token"), false),
+			Entry("Binding of another type", OnBinding(reflect.TypeOf(0), "helloservice.url"), false),
+			Entry("Missing binding", OnMissingBinding(reflect.TypeOf(""), "helloservice.token"), true),
+			Entry("Custom predicate", Condition(func(ConditionState) bool { return true }), true),
+		)
+
+		It("Checks the environment", func() {
+			Expect(OnEnv("INJECT_TEST_CONDITION")(state)).To(BeFalse())
+			os.Setenv("INJECT_TEST_CONDITION", "")
+			Expect(OnEnv("INJECT_TEST_CONDITION")(state)).To(BeTrue())
+		})
+	})
+
+	It("Falls back on a default when a binding is missing", func() {
+		fallback := CustomA{Val: 3}
+		fallbackProvider := &ConditionalProvider{
+			Provider: &ValueProvider{Value: &fallback},
+			When:     []Condition{OnMissingBinding(interfaceA, "")},
+		}
+
+		g = NewGraph(WithProfiles("prod"))
+		g.Provide(append(providers(), fallbackProvider)...)
+		Expect(g.Find(interfaceA, nil, "")).To(BeIdenticalTo(&prod))
+
+		g = NewGraph()
+		g.Provide(append(providers(), fallbackProvider)...)
+		Expect(g.Find(interfaceA, nil, "")).To(BeIdenticalTo(&fallback))
+	})
+
+	It("Completes conditional values on Resolve", func() {
+		type Needs struct {
+			D InterfaceA `inject:""`
+		}
+
+		needs := &Needs{}
+		g = NewGraph(WithProfiles("dev"))
+		g.Provide(append(providers(), &ConditionalProvider{
+			Provider: &ValueProvider{Value: needs},
+			When:     []Condition{OnProfile("dev")},
+		})...)
+
+		Expect(g.Resolve()).To(Succeed())
+		Expect(needs.D).To(BeIdenticalTo(&dev))
+	})
+
+	Describe("Wrapping a builder of a result object", func() {
+		type Pool struct{ Size int }
+
+		type Result struct {
+			Out
+
+			Pool *Pool
+			Name string `inject:"pool.name"`
+		}
+
+		builder := func() Provider {
+			return &ConditionalProvider{
+				Provider: &BuilderProvider{
+					Builder: func() Result {
+						return Result{Pool: &Pool{Size: 10}, Name: "dev"}
+					},
+				},
+				When: []Condition{OnProfile("dev")},
+			}
+		}
+
+		It("Provides the fields when active", func() {
+			g = NewGraph(WithProfiles("dev"))
+			g.Provide(builder())
+
+			pool, err := g.Find(reflect.TypeOf((*Pool)(nil)), nil, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pool.(*Pool).Size).To(Equal(10))
+			Expect(g.Find(reflect.TypeOf(""), nil, "pool.name")).To(Equal("dev"))
+		})
+
+		It("Does not provide the fields when inactive", func() {
+			g = NewGraph(WithProfiles("prod"))
+			g.Provide(builder())
+
+			_, err := g.Find(reflect.TypeOf((*Pool)(nil)), nil, "")
+			Expect(err).To(HaveOccurred())
+			_, err = g.Find(reflect.TypeOf(Result{}), nil, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("Delegates the type set of the wrapped provider", func() {
+		p := ConditionalProvider{
+			Provider: &ValueProvider{Value: &dev, As: []reflect.Type{interfaceA}},
+		}
+
+		Expect(p.GetAs()).To(Equal([]reflect.Type{interfaceA}))
+	})
+})
@@ -0,0 +1,138 @@
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigSource supplies named values from a JSON or YAML document. Nested
+// keys are flattened into dotted names, so {"helloservice": {"url": ...}}
+// answers "helloservice.url", while "helloservice" answers with the whole
+// subtree. Names are matched case-insensitively.
+type ConfigSource struct {
+	Path   string
+	values map[string]interface{}
+}
+
+// LoadConfigFile loads a JSON or YAML file, chosen by its extension.
+func LoadConfigFile(path string) (*ConfigSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var source *ConfigSource
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		source, err = LoadJSON(f)
+
+	case ".yaml", ".yml":
+		source, err = LoadYAML(f)
+
+	default:
+		return nil, fmt.Errorf("Unsupported config file type (%s).", path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Encountered error while trying to load (%s): %s", path, err)
+	}
+
+	source.Path = path
+	return source, nil
+}
+
+// LoadJSON loads a JSON document.
+func LoadJSON(r io.Reader) (*ConfigSource, error) {
+	var doc interface{}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return NewConfigSource(doc)
+}
+
+// LoadYAML loads a YAML document.
+func LoadYAML(r io.Reader) (*ConfigSource, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	return NewConfigSource(normalizeYAML(doc))
+}
+
+// NewConfigSource creates a source from an already decoded document, which
+// must be a map with string keys.
+func NewConfigSource(doc interface{}) (*ConfigSource, error) {
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Expected a map at the root of the config document, got %T.", doc)
+	}
+
+	source := &ConfigSource{values: map[string]interface{}{}}
+	source.flatten("", root)
+	return source, nil
+}
+
+func (s ConfigSource) Key(name string) string {
+	if s.Path == "" {
+		return "config key " + name
+	}
+
+	return fmt.Sprintf("config key %s in %s", name, s.Path)
+}
+
+func (s ConfigSource) Lookup(name string) (interface{}, bool) {
+	v, ok := s.values[strings.ToLower(name)]
+	return v, ok
+}
+
+func (s *ConfigSource) flatten(prefix string, node map[string]interface{}) {
+	for k, v := range node {
+		name := strings.ToLower(k)
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		s.values[name] = v
+		if child, ok := v.(map[string]interface{}); ok {
+			s.flatten(name, child)
+		}
+	}
+}
+
+// normalizeYAML converts the map[interface{}]interface{} maps produced by
+// the YAML decoder into map[string]interface{} so that they can be
+// flattened and re-encoded as JSON.
+func normalizeYAML(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, v := range n {
+			m[fmt.Sprint(k)] = normalizeYAML(v)
+		}
+		return m
+
+	case []interface{}:
+		for i, v := range n {
+			n[i] = normalizeYAML(v)
+		}
+		return n
+
+	default:
+		return node
+	}
+}
@@ -0,0 +1,167 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/impinj/go-inject/inject"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var _ = Describe("ConfigSource", func() {
+	type Endpoint struct {
+		Url     string
+		Timeout time.Duration
+		Tags    []string
+	}
+
+	const jsonDoc = `{
+		"helloservice": {
+			"url": "https://www.example.org/hello",
+			"port": 8080,
+			"tags": ["a", "b"],
+			"limits": {"read": 10, "write": 5},
+			"endpoint": {"url": "https://www.example.org", "timeout": "5s", "tags": ["c"]}
+		},
+		"debug": true,
+		"ratio": 1.9
+	}`
+
+	const yamlDoc = `
+helloservice:
+  url: https://www.example.org/hello
+  port: 8080
+  tags: [a, b]
+  limits:
+    read: 10
+    write: 5
+  endpoint:
+    url: https://www.example.org
+    timeout: 5s
+    tags: [c]
+debug: true
+ratio: 1.9
+`
+
+	var (
+		source *ConfigSource
+		g      Graph
+	)
+
+	for _, format := range []string{"JSON", "YAML"} {
+		format := format
+
+		Context("Loaded from "+format, func() {
+			BeforeEach(func() {
+				var err error
+				switch format {
+				case "JSON":
+					source, err = LoadJSON(strings.NewReader(jsonDoc))
+
+				case "YAML":
+					source, err = LoadYAML(strings.NewReader(yamlDoc))
+				}
+
+				Expect(err).ToNot(HaveOccurred())
+				g = NewGraph(WithSources(source))
+			})
+
+			DescribeTable("Finds named values of the requested type",
+				func(name string, expected interface{}) {
+					v, err := g.Find(reflect.TypeOf(expected), nil, name)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(v).To(Equal(expected))
+				},
+				Entry("String", "helloservice.url", "https://www.example.org/hello"),
+				Entry("Int", "helloservice.port", 8080),
+				Entry("Bool", "debug", true),
+				Entry("Slice", "helloservice.tags", []string{"a", "b"}),
+				Entry("Map", "helloservice.limits", map[string]int{"read": 10, "write": 5}),
+				Entry("Nested value", "helloservice.limits.read", 10),
+				Entry("Case-insensitive name", "HelloService.Url", "https://www.example.org/hello"),
+				Entry("Float", "ratio", 1.9),
+			)
+
+			DescribeTable("Rejects numbers which the requested type cannot hold",
+				func(name string, typeInfo reflect.Type, message string) {
+					_, err := g.Find(typeInfo, nil, name)
+					Expect(err).To(MatchError(ContainSubstring(message)))
+				},
+				Entry("Fraction", "ratio", reflect.TypeOf(0), "1.9 is not an integer"),
+				Entry("Overflow", "helloservice.port", reflect.TypeOf(int8(0)), "8080 overflows int8"),
+				Entry("Unsigned overflow", "helloservice.port", reflect.TypeOf(uint8(0)), "8080 overflows uint8"),
+			)
+
+			It("Decodes subtrees into structs", func() {
+				v, err := g.Find(reflect.TypeOf(Endpoint{}), nil, "helloservice.endpoint")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(v).To(Equal(Endpoint{
+					Url:     "https://www.example.org",
+					Timeout: 5 * time.Second,
+					Tags:    []string{"c"},
+				}))
+			})
+
+			It("Reports missing keys", func() {
+				_, ok := source.Lookup("helloservice.missing")
+				Expect(ok).To(BeFalse())
+
+				_, err := g.Find(reflect.TypeOf(""), nil, "helloservice.missing")
+				Expect(err).To(MatchError(ContainSubstring("config key helloservice.missing")))
+			})
+		})
+	}
+
+	Describe("LoadConfigFile", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "inject")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		DescribeTable("Chooses the format by extension",
+			func(file, contents string) {
+				path := filepath.Join(dir, file)
+				Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+
+				source, err := LoadConfigFile(path)
+				Expect(err).ToNot(HaveOccurred())
+				port, ok := source.Lookup("helloservice.port")
+				Expect(ok).To(BeTrue())
+				Expect(port).To(BeEquivalentTo(8080))
+				Expect(source.Key("helloservice.port")).To(Equal("config key helloservice.port in " + path))
+			},
+			Entry("JSON", "config.json", jsonDoc),
+			Entry("YAML", "config.yaml", yamlDoc),
+			Entry("YML", "config.yml", yamlDoc),
+		)
+
+		It("Rejects unknown extensions", func() {
+			path := filepath.Join(dir, "config.ini")
+			Expect(ioutil.WriteFile(path, []byte("port=8080"), 0600)).To(Succeed())
+
+			_, err := LoadConfigFile(path)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Rejects documents which are not maps", func() {
+			path := filepath.Join(dir, "config.json")
+			Expect(ioutil.WriteFile(path, []byte("[1, 2]"), 0600)).To(Succeed())
+
+			_, err := LoadConfigFile(path)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,292 @@
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// convertValue converts a raw value from a Source into typeInfo. Strings are
+// parsed according to the kind of typeInfo, with slices split on commas and
+// anything else decoded as JSON. Decoded trees of maps, slices and
+// primitives are converted recursively, matching map keys to struct fields
+// case-insensitively by field name or json tag. Errors for secret values,
+// including Secrets, do not include the value.
+func convertValue(raw interface{}, typeInfo reflect.Type, secret bool) (interface{}, error) {
+	if raw == nil {
+		return nil, fmt.Errorf("no value")
+	}
+
+	v, err := decodeValue(raw, typeInfo)
+	if err != nil {
+		if _, ok := raw.(Secret); ok || secret || typeInfo == secretType {
+			return nil, fmt.Errorf("secret value is invalid")
+		}
+
+		return nil, err
+	}
+
+	return v.Interface(), nil
+}
+
+func decodeValue(raw interface{}, typeInfo reflect.Type) (reflect.Value, error) {
+	v := reflect.New(typeInfo).Elem()
+	if raw == nil {
+		return v, nil
+	}
+
+	rawValue := reflect.ValueOf(raw)
+	if rawValue.Type().AssignableTo(typeInfo) {
+		v.Set(rawValue)
+		return v, nil
+	}
+
+	if secret, ok := raw.(Secret); ok {
+		return parseString(secret.Value(), typeInfo)
+	}
+
+	if s, ok := raw.(string); ok {
+		return parseString(s, typeInfo)
+	}
+
+	switch typeInfo.Kind() {
+	case reflect.Ptr:
+		el, err := decodeValue(raw, typeInfo.Elem())
+		if err != nil {
+			return v, err
+		}
+		v.Set(reflect.New(typeInfo.Elem()))
+		v.Elem().Set(el)
+
+	case reflect.Struct:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return v, fmt.Errorf("cannot decode %T into %s", raw, typeInfo)
+		}
+
+		for i := 0; i < typeInfo.NumField(); i++ {
+			fieldInfo := typeInfo.Field(i)
+			if fieldInfo.PkgPath != "" {
+				continue
+			}
+
+			if fieldRaw, ok := lookupFold(m, fieldKey(fieldInfo)); ok {
+				field, err := decodeValue(fieldRaw, fieldInfo.Type)
+				if err != nil {
+					return v, fmt.Errorf("%s: %s", fieldInfo.Name, err)
+				}
+				v.Field(i).Set(field)
+			}
+		}
+
+	case reflect.Map:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return v, fmt.Errorf("cannot decode %T into %s", raw, typeInfo)
+		}
+
+		v.Set(reflect.MakeMap(typeInfo))
+		for k, elRaw := range m {
+			key, err := parseString(k, typeInfo.Key())
+			if err != nil {
+				return v, fmt.Errorf("%s: %s", k, err)
+			}
+
+			el, err := decodeValue(elRaw, typeInfo.Elem())
+			if err != nil {
+				return v, fmt.Errorf("%s: %s", k, err)
+			}
+			v.SetMapIndex(key, el)
+		}
+
+	case reflect.Slice:
+		s, ok := raw.([]interface{})
+		if !ok {
+			return v, fmt.Errorf("cannot decode %T into %s", raw, typeInfo)
+		}
+
+		v.Set(reflect.MakeSlice(typeInfo, len(s), len(s)))
+		for i, elRaw := range s {
+			el, err := decodeValue(elRaw, typeInfo.Elem())
+			if err != nil {
+				return v, fmt.Errorf("%d: %s", i, err)
+			}
+			v.Index(i).Set(el)
+		}
+
+	default:
+		if !rawValue.Type().ConvertibleTo(typeInfo) ||
+			rawValue.Kind() == reflect.String ||
+			(rawValue.Kind() == reflect.Bool) != (typeInfo.Kind() == reflect.Bool) {
+			return v, fmt.Errorf("cannot decode %T into %s", raw, typeInfo)
+		}
+
+		if err := checkInteger(rawValue, typeInfo); err != nil {
+			return v, err
+		}
+		v.Set(rawValue.Convert(typeInfo))
+	}
+
+	return v, nil
+}
+
+// checkInteger reports numbers which cannot be converted to an integer type
+// without truncating a fraction or overflowing.
+func checkInteger(rawValue reflect.Value, typeInfo reflect.Type) error {
+	var min, max float64
+	switch typeInfo.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		min, max = -math.Ldexp(1, typeInfo.Bits()-1), math.Ldexp(1, typeInfo.Bits()-1)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		min, max = 0, math.Ldexp(1, typeInfo.Bits())
+
+	default:
+		return nil
+	}
+
+	var f float64
+	switch rawValue.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f = rawValue.Float()
+		if f != math.Trunc(f) {
+			return fmt.Errorf("%v is not an integer", f)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(rawValue.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f = float64(rawValue.Uint())
+
+	default:
+		return nil
+	}
+
+	if f < min || f >= max {
+		return fmt.Errorf("%v overflows %s", rawValue.Interface(), typeInfo)
+	}
+
+	return nil
+}
+
+func fieldKey(fieldInfo reflect.StructField) string {
+	if tag := strings.Split(fieldInfo.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+		return tag
+	}
+
+	return fieldInfo.Name
+}
+
+func lookupFold(m map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+func parseString(s string, typeInfo reflect.Type) (reflect.Value, error) {
+	v := reflect.New(typeInfo).Elem()
+	if typeInfo == secretType {
+		v.Set(reflect.ValueOf(NewSecret(s)))
+		return v, nil
+	}
+
+	switch typeInfo.Kind() {
+	case reflect.String:
+		v.SetString(s)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if typeInfo == durationType {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return v, err
+			}
+			v.SetInt(int64(d))
+			break
+		}
+
+		i, err := strconv.ParseInt(s, 0, typeInfo.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 0, typeInfo.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, typeInfo.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+
+	case reflect.Ptr:
+		el, err := parseString(s, typeInfo.Elem())
+		if err != nil {
+			return v, err
+		}
+		v.Set(reflect.New(typeInfo.Elem()))
+		v.Elem().Set(el)
+
+	case reflect.Slice:
+		if typeInfo.Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(s))
+			break
+		}
+
+		if strings.TrimSpace(s) == "" {
+			v.Set(reflect.MakeSlice(typeInfo, 0, 0))
+			break
+		}
+
+		parts := strings.Split(s, ",")
+		v.Set(reflect.MakeSlice(typeInfo, len(parts), len(parts)))
+		for i, part := range parts {
+			el, err := parseString(strings.TrimSpace(part), typeInfo.Elem())
+			if err != nil {
+				return v, err
+			}
+			v.Index(i).Set(el)
+		}
+
+	default:
+		var doc interface{}
+		if err := json.Unmarshal([]byte(s), &doc); err != nil {
+			return v, err
+		}
+
+		if _, ok := doc.(string); ok {
+			return v, fmt.Errorf("cannot decode %q into %s", s, typeInfo)
+		}
+
+		return decodeValue(doc, typeInfo)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,158 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// dependency is a single request a provider makes of the graph: an
+// inject-tagged field of the type it provides or of a struct builder
+// parameter, or any other builder parameter.
+type dependency struct {
+	field    string
+	typeInfo reflect.Type
+	context  reflect.Type
+	name     string
+	optional bool
+	secret   bool
+}
+
+// supply describes what the graph would use to satisfy a dependency. At most
+// one of provider, source and jit is set; problem explains why none is.
+type supply struct {
+	provider Provider
+	source   string
+	jit      reflect.Type
+	problem  string
+}
+
+func (s supply) resolved() bool {
+	return s.problem == ""
+}
+
+// dependencies returns the requests a provider makes of the graph, without
+// resolving it.
+func dependencies(provider Provider) []dependency {
+	var deps []dependency
+
+	if builder := builderOf(provider); builder != nil {
+		typeInfo := reflect.TypeOf(builder.Builder)
+		if typeInfo != nil && typeInfo.Kind() == reflect.Func {
+			for i := 0; i < typeInfo.NumIn(); i++ {
+				argTypeInfo := typeInfo.In(i)
+				if argTypeInfo.Kind() == reflect.Struct {
+					deps = append(deps, fieldDependencies(argTypeInfo)...)
+					continue
+				}
+
+				deps = append(deps, dependency{
+					field:    fmt.Sprintf("arg %d", i),
+					typeInfo: argTypeInfo,
+				})
+			}
+		}
+	}
+
+	if typeInfo := providerType(provider); typeInfo != nil {
+		deps = append(deps, fieldDependencies(typeInfo)...)
+	}
+
+	return deps
+}
+
+// fieldDependencies returns the inject-tagged fields of a struct, or of the
+// struct a pointer refers to.
+func fieldDependencies(typeInfo reflect.Type) []dependency {
+	for typeInfo.Kind() == reflect.Ptr {
+		typeInfo = typeInfo.Elem()
+	}
+
+	if typeInfo.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var deps []dependency
+	for i := 0; i < typeInfo.NumField(); i++ {
+		fieldInfo := typeInfo.Field(i)
+		if _, ok := structTagLookup(fieldInfo.Tag, injectTag); !ok {
+			continue
+		}
+
+		options := parseInjectTag(fieldInfo.Tag.Get(injectTag))
+		deps = append(deps, dependency{
+			field:    typeInfo.Name() + "." + fieldInfo.Name,
+			typeInfo: fieldInfo.Type,
+			context:  typeInfo,
+			name:     options.name,
+			optional: options.optional,
+			secret:   options.secret,
+		})
+	}
+
+	return deps
+}
+
+// supplier reports what would satisfy a dependency, following the same
+// rules as find but without resolving anything or adding bindings.
+func (g *graph) supplier(dep dependency) supply {
+	candidates := selectProviders(g.active(), dep.typeInfo, dep.context, dep.name)
+	switch {
+	case len(candidates) == 1:
+		return supply{provider: candidates[0]}
+
+	case len(candidates) > 1:
+		return supply{problem: fmt.Sprintf("%d providers", len(candidates))}
+	}
+
+	if dep.name != "" && len(g.sources) > 0 {
+		for _, source := range g.sources {
+			if _, ok := source.Lookup(dep.name); ok {
+				return supply{source: source.Key(dep.name)}
+			}
+		}
+
+		return supply{problem: "missing"}
+	}
+
+	if g.mode == JustInTime &&
+		dep.name == "" &&
+		dep.typeInfo.Kind() == reflect.Ptr &&
+		dep.typeInfo.Elem().Kind() == reflect.Struct &&
+		len(selectProvidersByType(g.active(), dep.typeInfo)) == 0 {
+		return supply{jit: dep.typeInfo}
+	}
+
+	return supply{problem: "missing"}
+}
+
+// providerKind names the kind of a provider, e.g. BuilderProvider.
+func providerKind(provider Provider) string {
+	if p, ok := provider.(*resultProvider); ok {
+		provider = p.result.provider
+	}
+
+	typeInfo := reflect.TypeOf(provider)
+	for typeInfo.Kind() == reflect.Ptr {
+		typeInfo = typeInfo.Elem()
+	}
+
+	return typeInfo.Name()
+}
+
+// indexOfProvider returns the position of a provider in a list, or -1.
+// Providers passed by value may not be comparable, so they are matched by
+// deep equality instead.
+func indexOfProvider(providers []Provider, provider Provider) int {
+	comparable := reflect.TypeOf(provider).Comparable()
+	for i, candidate := range providers {
+		if comparable && reflect.TypeOf(candidate).Comparable() {
+			if candidate == provider {
+				return i
+			}
+		} else if reflect.DeepEqual(candidate, provider) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,191 @@
+package inject
+
+import "reflect"
+
+// Description is a serializable model of a graph's bindings, as returned by
+// Graph.Describe. It marshals to JSON for use by tooling.
+type Description struct {
+	Providers []ProviderDescription `json:"providers"`
+
+	// Completed lists the structs completed by the graph which no provider
+	// provides, such as those passed directly to Complete.
+	Completed []CompletionDescription `json:"completed,omitempty"`
+}
+
+// ProviderDescription describes an active provider. Its position in
+// Description.Providers is its ID.
+type ProviderDescription struct {
+	ID           int                     `json:"id"`
+	Kind         string                  `json:"kind"`
+	Type         string                  `json:"type,omitempty"`
+	Name         string                  `json:"name,omitempty"`
+	Context      string                  `json:"context,omitempty"`
+	Complete     bool                    `json:"complete"`
+	Singleton    bool                    `json:"singleton"`
+	Dependencies []DependencyDescription `json:"dependencies,omitempty"`
+}
+
+// CompletionDescription describes a struct completed by the graph and what
+// supplied each of its fields.
+type CompletionDescription struct {
+	Type         string                  `json:"type"`
+	Dependencies []DependencyDescription `json:"dependencies,omitempty"`
+}
+
+// DependencyDescription describes an inject-tagged field or builder
+// parameter of a provider and what supplies it: another provider, a
+// source, or a just-in-time binding. Problem is set when nothing does.
+// Observed is set when the field has been injected and the supplier is the
+// one which was used, rather than the one which would be.
+type DependencyDescription struct {
+	Field      string `json:"field"`
+	Type       string `json:"type"`
+	Name       string `json:"name,omitempty"`
+	Optional   bool   `json:"optional,omitempty"`
+	Secret     bool   `json:"secret,omitempty"`
+	Provider   *int   `json:"provider,omitempty"`
+	Source     string `json:"source,omitempty"`
+	JustInTime string `json:"justInTime,omitempty"`
+	Problem    string `json:"problem,omitempty"`
+	Observed   bool   `json:"observed,omitempty"`
+}
+
+// Describe returns a model of the graph's active providers and of what
+// supplies each of their dependencies. Fields which Complete has injected
+// report the supplier which was used; the others report the supplier which
+// would be, without resolving anything.
+func (g *graph) Describe() Description {
+	providers := g.active()
+	provided := map[reflect.Type]bool{}
+
+	description := Description{Providers: []ProviderDescription{}}
+	for i, provider := range providers {
+		p := ProviderDescription{
+			ID:           i,
+			Kind:         providerKind(provider),
+			Name:         provider.GetName(),
+			Complete:     provider.IsComplete(),
+			Singleton:    isSingleton(provider),
+			Dependencies: g.describeDependencies(providers, dependencies(provider)),
+		}
+
+		if typeInfo := providerType(provider); typeInfo != nil {
+			p.Type = typeInfo.String()
+			provided[derefType(typeInfo)] = true
+		}
+
+		if context := provider.GetContext(); context != nil {
+			p.Context = context.String()
+		}
+
+		description.Providers = append(description.Providers, p)
+	}
+
+	for _, typeInfo := range g.completedTypes {
+		if provided[typeInfo] {
+			continue
+		}
+
+		description.Completed = append(description.Completed, CompletionDescription{
+			Type:         typeInfo.String(),
+			Dependencies: g.describeDependencies(providers, fieldDependencies(typeInfo)),
+		})
+	}
+
+	return description
+}
+
+func (g *graph) describeDependencies(providers []Provider, deps []dependency) []DependencyDescription {
+	var descriptions []DependencyDescription
+	for _, dep := range deps {
+		d := DependencyDescription{
+			Field:    dep.field,
+			Type:     dep.typeInfo.String(),
+			Name:     dep.name,
+			Optional: dep.optional,
+			Secret:   dep.secret,
+		}
+
+		s, ok := g.completed[dep.context][dep.field]
+		if ok && s.provider != nil && indexOfProvider(providers, s.provider) < 0 {
+			ok = false
+		}
+		if !ok {
+			s = g.supplier(dep)
+		}
+		d.Observed = ok
+
+		switch {
+		case s.provider != nil:
+			id := indexOfProvider(providers, s.provider)
+			d.Provider = &id
+
+		case s.source != "":
+			d.Source = s.source
+
+		case s.jit != nil:
+			d.JustInTime = s.jit.String()
+
+		default:
+			d.Problem = s.problem
+		}
+
+		descriptions = append(descriptions, d)
+	}
+
+	return descriptions
+}
+
+// recordCompletion remembers what supplied a field set by Complete, for
+// Describe.
+func (g *graph) recordCompletion(owner reflect.Type, field string, name string, provider Provider) {
+	if g.completed == nil {
+		g.completed = map[reflect.Type]map[string]supply{}
+	}
+
+	fields, ok := g.completed[owner]
+	if !ok {
+		fields = map[string]supply{}
+		g.completed[owner] = fields
+		g.completedTypes = append(g.completedTypes, owner)
+	}
+
+	s := supply{provider: provider}
+	if name != "" && indexOfProvider(g.active(), provider) < 0 {
+		for _, source := range g.sources {
+			if _, ok := source.Lookup(name); ok {
+				s = supply{source: source.Key(name)}
+				break
+			}
+		}
+	}
+	fields[owner.Name()+"."+field] = s
+}
+
+func derefType(typeInfo reflect.Type) reflect.Type {
+	for typeInfo.Kind() == reflect.Ptr {
+		typeInfo = typeInfo.Elem()
+	}
+
+	return typeInfo
+}
+
+// isSingleton reports whether a provider resolves to the same value every
+// time.
+func isSingleton(provider Provider) bool {
+	switch p := provider.(type) {
+	case *ValueProvider, ValueProvider, *SingletonProvider, *outputProvider, outputProvider, *resultProvider:
+		return true
+
+	case *TypeProvider:
+		return p.Scope == Singleton
+
+	case *ConditionalProvider:
+		return isSingleton(p.Provider)
+
+	case ConditionalProvider:
+		return isSingleton(p.Provider)
+	}
+
+	return false
+}
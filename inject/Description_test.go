@@ -0,0 +1,148 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	. "github.com/impinj/go-inject/inject"
+	"reflect"
+)
+
+var _ = Describe("Describe", func() {
+	It("Describes providers", func() {
+		g := NewGraph()
+		g.Provide(
+			&ValueProvider{Value: &ServicePtrImpl{}},
+			&ValueProvider{Name: "a", Value: CustomA{}, Context: reflect.TypeOf(ServicePtrImpl{})},
+			&TypeProvider{Type: reflect.TypeOf(StructA{}), Scope: Singleton, ResolveContext: g},
+			&BuilderProvider{Builder: func() *StructB { return &StructB{} }})
+
+		d := g.Describe()
+		Expect(d.Providers).To(HaveLen(4))
+
+		Expect(d.Providers[0].ID).To(Equal(0))
+		Expect(d.Providers[0].Kind).To(Equal("ValueProvider"))
+		Expect(d.Providers[0].Type).To(Equal("*inject_test.ServicePtrImpl"))
+		Expect(d.Providers[0].Singleton).To(BeTrue())
+
+		Expect(d.Providers[1].Name).To(Equal("a"))
+		Expect(d.Providers[1].Context).To(Equal("inject_test.ServicePtrImpl"))
+
+		Expect(d.Providers[2].Kind).To(Equal("TypeProvider"))
+		Expect(d.Providers[2].Singleton).To(BeTrue())
+		Expect(d.Providers[2].Complete).To(BeFalse())
+
+		Expect(d.Providers[3].Kind).To(Equal("BuilderProvider"))
+		Expect(d.Providers[3].Singleton).To(BeFalse())
+	})
+
+	It("Describes which provider supplies each field", func() {
+		g := NewGraph()
+		g.Provide(
+			&ValueProvider{Value: &ServicePtrImpl{}},
+			&ValueProvider{Value: CustomA{}})
+		Expect(g.Resolve()).To(Succeed())
+
+		deps := g.Describe().Providers[0].Dependencies
+		Expect(deps).To(HaveLen(1))
+		Expect(deps[0].Field).To(Equal("ServicePtrImpl.X"))
+		Expect(deps[0].Type).To(Equal("inject_test.InterfaceA"))
+		Expect(deps[0].Provider).NotTo(BeNil())
+		Expect(*deps[0].Provider).To(Equal(1))
+		Expect(deps[0].Problem).To(BeEmpty())
+	})
+
+	It("Describes every builder parameter", func() {
+		g := NewGraph()
+		g.Provide(
+			&BuilderProvider{Builder: func(c *DotConfigured, a InterfaceA) *StructB {
+				return &StructB{}
+			}},
+			&ValueProvider{Value: &DotConfigured{}},
+			&ValueProvider{Value: CustomA{}})
+
+		deps := g.Describe().Providers[0].Dependencies
+		Expect(deps).To(HaveLen(3))
+		Expect(deps[0].Field).To(Equal("arg 0"))
+		Expect(deps[0].Type).To(Equal("*inject_test.DotConfigured"))
+		Expect(*deps[0].Provider).To(Equal(1))
+		Expect(deps[1].Field).To(Equal("arg 1"))
+		Expect(*deps[1].Provider).To(Equal(2))
+		Expect(deps[2].Field).To(Equal("StructB.A"))
+	})
+
+	It("Describes sources and unresolved dependencies", func() {
+		g := NewGraph(WithSources(MapSource{"url": "http://example.com"}))
+		g.Provide(
+			&ValueProvider{Value: &DotConfigured{}},
+			&ValueProvider{Value: &DotOptional{}})
+
+		d := g.Describe()
+		Expect(d.Providers[0].Dependencies[0].Source).To(Equal("map entry url"))
+		Expect(d.Providers[1].Dependencies[0].Optional).To(BeTrue())
+		Expect(d.Providers[1].Dependencies[0].Problem).To(Equal("missing"))
+		Expect(d.Providers[1].Dependencies[0].Provider).To(BeNil())
+	})
+
+	It("Includes just-in-time bindings made while resolving", func() {
+		g := NewGraph(WithMode(JustInTime))
+		g.Provide(&ValueProvider{Value: &StructA{}})
+
+		Expect(g.Describe().Providers[0].Dependencies[0].JustInTime).To(Equal("*inject_test.StructB"))
+
+		Expect(g.Resolve()).To(Succeed())
+		d := g.Describe()
+		Expect(d.Providers).To(HaveLen(2))
+		Expect(d.Providers[1].Kind).To(Equal("TypeProvider"))
+		Expect(*d.Providers[0].Dependencies[0].Provider).To(Equal(1))
+	})
+
+	It("Describes the provider used for fields which have been injected", func() {
+		g := NewGraph()
+		g.Provide(
+			&ValueProvider{Value: &ServicePtrImpl{}},
+			&ValueProvider{Value: CustomA{}})
+		Expect(g.Resolve()).To(Succeed())
+		g.Provide(&ValueProvider{Value: &CustomA{}})
+
+		deps := g.Describe().Providers[0].Dependencies
+		Expect(deps[0].Observed).To(BeTrue())
+		Expect(*deps[0].Provider).To(Equal(1))
+		Expect(deps[0].Problem).To(BeEmpty())
+	})
+
+	It("Describes structs completed directly", func() {
+		g := NewGraph(WithSources(MapSource{"url": "http://example.com"}))
+		g.Provide(&ValueProvider{Value: CustomA{}})
+
+		var s ServicePtrImpl
+		Expect(g.Complete(&s)).To(Succeed())
+		var c DotConfigured
+		Expect(g.Complete(&c)).To(Succeed())
+
+		d := g.Describe()
+		Expect(d.Completed).To(HaveLen(2))
+		Expect(d.Completed[0].Type).To(Equal("inject_test.ServicePtrImpl"))
+		Expect(d.Completed[0].Dependencies).To(HaveLen(1))
+		Expect(d.Completed[0].Dependencies[0].Observed).To(BeTrue())
+		Expect(*d.Completed[0].Dependencies[0].Provider).To(Equal(0))
+		Expect(d.Completed[1].Dependencies[0].Source).To(Equal("map entry url"))
+		Expect(d.Completed[1].Dependencies[0].Observed).To(BeTrue())
+	})
+
+	It("Marshals to JSON", func() {
+		g := NewGraph()
+		g.Provide(
+			&ValueProvider{Value: &ServicePtrImpl{}},
+			&ValueProvider{Value: CustomA{}})
+
+		data, err := json.Marshal(g.Describe())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(MatchJSON(`{"providers": [
+			{"id": 0, "kind": "ValueProvider", "type": "*inject_test.ServicePtrImpl", "complete": false, "singleton": true,
+			 "dependencies": [{"field": "ServicePtrImpl.X", "type": "inject_test.InterfaceA", "provider": 1}]},
+			{"id": 1, "kind": "ValueProvider", "type": "inject_test.CustomA", "complete": false, "singleton": true}
+		]}`))
+	})
+})
@@ -0,0 +1,118 @@
+package inject
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// WriteDot writes the graph in Graphviz DOT format: a node for every active
+// provider and an edge for every dependency of its builder parameters and
+// inject-tagged fields. Dependencies which could not be resolved are drawn
+// as dashed red edges to a node describing the request; optional ones are
+// dotted and grey. Nothing is resolved to produce the description.
+func (g *graph) WriteDot(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("digraph inject {\n")
+	buf.WriteString("\tnode [shape=box];\n")
+
+	providers := g.active()
+	for i, provider := range providers {
+		fmt.Fprintf(&buf, "\tp%d [label=%s];\n", i, strconv.Quote(describeProvider(provider)))
+	}
+
+	sources := map[string]string{}
+	jits := map[reflect.Type]string{}
+	unresolved := 0
+
+	type owner struct {
+		id   string
+		deps []dependency
+	}
+
+	var queue []owner
+	for i, provider := range providers {
+		queue = append(queue, owner{fmt.Sprintf("p%d", i), dependencies(provider)})
+	}
+
+	for len(queue) > 0 {
+		o := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range o.deps {
+			s := g.supplier(dep)
+
+			var target, attributes string
+			switch {
+			case s.provider != nil:
+				target = fmt.Sprintf("p%d", indexOfProvider(providers, s.provider))
+
+			case s.source != "":
+				if _, ok := sources[s.source]; !ok {
+					sources[s.source] = fmt.Sprintf("s%d", len(sources))
+					fmt.Fprintf(&buf, "\t%s [label=%s, shape=note];\n", sources[s.source], strconv.Quote(s.source))
+				}
+				target = sources[s.source]
+
+			case s.jit != nil:
+				if _, ok := jits[s.jit]; !ok {
+					jits[s.jit] = fmt.Sprintf("j%d", len(jits))
+					fmt.Fprintf(&buf, "\t%s [label=%s, style=dashed];\n", jits[s.jit], strconv.Quote("just in time\n"+s.jit.String()))
+					queue = append(queue, owner{jits[s.jit], fieldDependencies(s.jit)})
+				}
+				target = jits[s.jit]
+
+			default:
+				target = fmt.Sprintf("u%d", unresolved)
+				unresolved++
+
+				style, color := "dashed", "red"
+				if dep.optional {
+					style, color = "dotted", "grey"
+				}
+
+				fmt.Fprintf(&buf, "\t%s [label=%s, shape=ellipse, color=%s, fontcolor=%s];\n",
+					target,
+					strconv.Quote(s.problem+"\n"+describeRequest(dep)),
+					color,
+					color)
+				attributes = fmt.Sprintf(", style=%s, color=%s, fontcolor=%s", style, color, color)
+			}
+
+			fmt.Fprintf(&buf, "\t%s -> %s [label=%s%s];\n", o.id, target, strconv.Quote(dep.field), attributes)
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func describeProvider(provider Provider) string {
+	description := providerKind(provider)
+	if typeInfo := providerType(provider); typeInfo != nil {
+		description += "\n" + typeInfo.String()
+	}
+
+	if name := provider.GetName(); name != "" {
+		description += "\nname: " + name
+	}
+
+	if context := provider.GetContext(); context != nil {
+		description += "\ncontext: " + context.String()
+	}
+
+	return description
+}
+
+func describeRequest(dep dependency) string {
+	description := dep.typeInfo.String()
+	if dep.name != "" {
+		description += "\nname: " + dep.name
+	}
+
+	return description
+}
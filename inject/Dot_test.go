@@ -0,0 +1,114 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	. "github.com/impinj/go-inject/inject"
+	"reflect"
+)
+
+type DotOptional struct {
+	A InterfaceA `inject:",optional"`
+}
+
+type DotConfigured struct {
+	URL string `inject:"url"`
+}
+
+var _ = Describe("WriteDot", func() {
+	dot := func(g Graph) string {
+		var buf bytes.Buffer
+		Expect(g.WriteDot(&buf)).To(Succeed())
+		return buf.String()
+	}
+
+	It("Writes a node per provider and an edge per resolved field", func() {
+		g := NewGraph()
+		g.Provide(
+			&ValueProvider{Value: &ServicePtrImpl{}},
+			&ValueProvider{Name: "a", Value: CustomA{}, Context: reflect.TypeOf(ServicePtrImpl{})})
+
+		out := dot(g)
+		Expect(out).To(HavePrefix("digraph inject {\n"))
+		Expect(out).To(ContainSubstring(`p0 [label="ValueProvider\n*inject_test.ServicePtrImpl"];`))
+		Expect(out).To(ContainSubstring(`p1 [label="ValueProvider\ninject_test.CustomA\nname: a\ncontext: inject_test.ServicePtrImpl"];`))
+		Expect(out).To(HaveSuffix("}\n"))
+	})
+
+	It("Draws edges to the providers which satisfy fields", func() {
+		g := NewGraph()
+		g.Provide(
+			&ValueProvider{Value: &ServicePtrImpl{}},
+			&ValueProvider{Value: CustomA{}})
+
+		Expect(dot(g)).To(ContainSubstring(`p0 -> p1 [label="ServicePtrImpl.X"];`))
+	})
+
+	It("Draws unresolved edges distinctly", func() {
+		g := NewGraph()
+		g.Provide(
+			&ValueProvider{Value: &ServicePtrImpl{}},
+			&ValueProvider{Value: &DotOptional{}})
+
+		out := dot(g)
+		Expect(out).To(ContainSubstring(`u0 [label="missing\ninject_test.InterfaceA", shape=ellipse, color=red, fontcolor=red];`))
+		Expect(out).To(ContainSubstring(`p0 -> u0 [label="ServicePtrImpl.X", style=dashed, color=red, fontcolor=red];`))
+		Expect(out).To(ContainSubstring(`p1 -> u1 [label="DotOptional.A", style=dotted, color=grey, fontcolor=grey];`))
+	})
+
+	It("Reports ambiguous requests as unresolved", func() {
+		g := NewGraph()
+		g.Provide(
+			&ValueProvider{Value: &ServicePtrImpl{}},
+			&ValueProvider{Value: CustomA{Val: 1}},
+			&ValueProvider{Value: CustomA{Val: 2}})
+
+		Expect(dot(g)).To(ContainSubstring(`u0 [label="2 providers\ninject_test.InterfaceA"`))
+	})
+
+	It("Draws builder parameters", func() {
+		built := false
+		g := NewGraph()
+		g.Provide(
+			&BuilderProvider{Builder: func(a InterfaceA) *StructA {
+				built = true
+				return &StructA{}
+			}},
+			&ValueProvider{Value: CustomA{}})
+
+		out := dot(g)
+		Expect(out).To(ContainSubstring(`p0 -> p1 [label="arg 0"];`))
+		Expect(out).To(ContainSubstring(`p0 -> u0 [label="StructA.B"`))
+		Expect(built).To(BeFalse())
+	})
+
+	It("Draws builder parameters of any type", func() {
+		g := NewGraph()
+		g.Provide(
+			&BuilderProvider{Builder: func(c *DotConfigured, retries int) *ServicePtrImpl {
+				return &ServicePtrImpl{}
+			}},
+			&ValueProvider{Value: &DotConfigured{}})
+
+		out := dot(g)
+		Expect(out).To(ContainSubstring(`p0 -> p1 [label="arg 0"];`))
+		Expect(out).To(ContainSubstring(`u0 [label="missing\nint"`))
+		Expect(out).To(ContainSubstring(`p0 -> u0 [label="arg 1"`))
+	})
+
+	It("Draws sources and just-in-time bindings", func() {
+		g := NewGraph(WithMode(JustInTime), WithSources(MapSource{"url": "http://example.com"}))
+		g.Provide(
+			&ValueProvider{Value: &DotConfigured{}},
+			&ValueProvider{Value: &StructA{}})
+
+		out := dot(g)
+		Expect(out).To(ContainSubstring(`s0 [label="map entry url", shape=note];`))
+		Expect(out).To(ContainSubstring(`p0 -> s0 [label="DotConfigured.URL"];`))
+		Expect(out).To(ContainSubstring(`j0 [label="just in time\n*inject_test.StructB", style=dashed];`))
+		Expect(out).To(ContainSubstring(`p1 -> j0 [label="StructA.B"];`))
+		Expect(out).To(ContainSubstring(`j0 -> p1 [label="StructB.A"];`))
+	})
+})
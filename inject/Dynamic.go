@@ -0,0 +1,70 @@
+//go:build go1.18
+// +build go1.18
+
+package inject
+
+import "sync"
+
+// Dynamic is a typed handle on a named value which may change while the
+// process runs. Fields of type *Dynamic[T] with a name, such as
+// `inject:"ratelimit"`, are given a handle whose Get always returns the
+// current value converted to T. A value which cannot be converted fails the
+// injection; after a reload, it is ignored and the last good value is kept.
+type Dynamic[T any] struct {
+	value *DynamicValue
+
+	mutex       sync.RWMutex
+	current     T
+	subscribers []func(T)
+}
+
+// Name returns the name of the value.
+func (d *Dynamic[T]) Name() string {
+	return d.value.Name()
+}
+
+// Get returns the current value.
+func (d *Dynamic[T]) Get() T {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return d.current
+}
+
+// Subscribe registers a function which is called with the new value
+// whenever it changes.
+func (d *Dynamic[T]) Subscribe(subscriber func(T)) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.subscribers = append(d.subscribers, subscriber)
+}
+
+func (d *Dynamic[T]) bind(value *DynamicValue) error {
+	if err := value.Decode(&d.current); err != nil {
+		return err
+	}
+
+	d.value = value
+	value.Subscribe(func(*DynamicValue) {
+		d.refresh()
+	})
+
+	return nil
+}
+
+func (d *Dynamic[T]) refresh() {
+	var current T
+	if err := d.value.Decode(&current); err != nil {
+		return
+	}
+
+	d.mutex.Lock()
+	d.current = current
+	subscribers := append([]func(T){}, d.subscribers...)
+	d.mutex.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(current)
+	}
+}
@@ -0,0 +1,162 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var dynamicValueType = reflect.TypeOf((*DynamicValue)(nil))
+
+// DynamicValue is an untyped handle on a named value which may change while
+// the process runs, e.g. a rate limit read from a reloadable source. Fields
+// of type *DynamicValue with a name, such as `inject:"ratelimit"`, are given
+// a handle which always reflects the current value of the graph's sources.
+// Every field with the same name shares one handle. On Go 1.18 and later,
+// Dynamic[T] offers the same with the value already converted.
+type DynamicValue struct {
+	name    string
+	sources []Source
+
+	mutex       sync.RWMutex
+	value       interface{}
+	subscribers []func(*DynamicValue)
+}
+
+// dynamicHandle is implemented by typed handles, which follow the
+// DynamicValue for their name.
+type dynamicHandle interface {
+	bind(value *DynamicValue) error
+}
+
+var dynamicHandleType = reflect.TypeOf((*dynamicHandle)(nil)).Elem()
+
+// dynamicKey identifies a handle shared by the fields of a graph.
+type dynamicKey struct {
+	typeInfo reflect.Type
+	name     string
+}
+
+func isDynamic(typeInfo reflect.Type) bool {
+	return typeInfo == dynamicValueType ||
+		(typeInfo.Kind() == reflect.Ptr && typeInfo.Implements(dynamicHandleType))
+}
+
+// dynamic returns the graph's handle of type typeInfo on name, creating it
+// on first use so that every lookup shares one handle and one reload
+// listener.
+func (g *graph) dynamic(typeInfo reflect.Type, name string) (interface{}, error) {
+	if handle, ok := g.dynamics[dynamicKey{typeInfo, name}]; ok {
+		return handle, nil
+	}
+
+	if g.dynamics == nil {
+		g.dynamics = map[dynamicKey]interface{}{}
+	}
+
+	value, ok := g.dynamics[dynamicKey{dynamicValueType, name}].(*DynamicValue)
+	if !ok {
+		value = newDynamicValue(name, g.sources)
+		g.dynamics[dynamicKey{dynamicValueType, name}] = value
+	}
+
+	if typeInfo == dynamicValueType {
+		return value, nil
+	}
+
+	handle := reflect.New(typeInfo.Elem()).Interface()
+	if err := handle.(dynamicHandle).bind(value); err != nil {
+		return nil, err
+	}
+	g.dynamics[dynamicKey{typeInfo, name}] = handle
+
+	return handle, nil
+}
+
+func newDynamicValue(name string, sources []Source) *DynamicValue {
+	d := &DynamicValue{
+		name:    name,
+		sources: sources,
+	}
+	d.value, _ = d.lookup()
+
+	for _, source := range sources {
+		if notifier, ok := source.(ReloadNotifier); ok {
+			notifier.OnReload(d.refresh)
+		}
+	}
+
+	return d
+}
+
+// Name returns the name of the value.
+func (d *DynamicValue) Name() string {
+	return d.name
+}
+
+// Decode converts the current value into v, which must be a pointer.
+func (d *DynamicValue) Decode(v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("Tried to decode into a non-pointer object (%T).", v)
+	}
+
+	raw, ok := d.Raw()
+	if !ok {
+		return fmt.Errorf("No value for %s.", d.name)
+	}
+
+	value, err := convertValue(raw, target.Type().Elem(), false)
+	if err != nil {
+		return fmt.Errorf("Could not convert %s to %s: %s.", d.name, target.Type().Elem(), err)
+	}
+
+	target.Elem().Set(reflect.ValueOf(value))
+	return nil
+}
+
+// Raw returns the current value as supplied by the source, and whether
+// there is one.
+func (d *DynamicValue) Raw() (interface{}, bool) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return d.value, d.value != nil
+}
+
+// Subscribe registers a function which is called whenever the value
+// changes.
+func (d *DynamicValue) Subscribe(subscriber func(*DynamicValue)) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.subscribers = append(d.subscribers, subscriber)
+}
+
+func (d *DynamicValue) lookup() (interface{}, bool) {
+	for _, source := range d.sources {
+		if raw, ok := source.Lookup(d.name); ok {
+			return raw, true
+		}
+	}
+
+	return nil, false
+}
+
+func (d *DynamicValue) refresh() {
+	value, _ := d.lookup()
+
+	d.mutex.Lock()
+	if reflect.DeepEqual(value, d.value) {
+		d.mutex.Unlock()
+		return
+	}
+
+	d.value = value
+	subscribers := append([]func(*DynamicValue){}, d.subscribers...)
+	d.mutex.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(d)
+	}
+}
@@ -0,0 +1,107 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/impinj/go-inject/inject"
+	"reflect"
+	"time"
+)
+
+// countingSource counts the listeners registered with a reloadable source.
+type countingSource struct {
+	*ReloadableSource
+	listeners int
+}
+
+func (s *countingSource) OnReload(listener func()) {
+	s.listeners++
+	s.ReloadableSource.OnReload(listener)
+}
+
+var _ = Describe("DynamicValue", func() {
+	type Service struct {
+		RateLimit *DynamicValue `inject:"ratelimit"`
+		Timeout   *DynamicValue `inject:"timeout,optional"`
+	}
+
+	var (
+		values  MapSource
+		source  *ReloadableSource
+		counter *countingSource
+		g       Graph
+		service Service
+	)
+
+	BeforeEach(func() {
+		values = MapSource{"ratelimit": "10"}
+
+		var err error
+		source, err = NewReloadableSource(func() (Source, error) {
+			return values, nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		service = Service{}
+		counter = &countingSource{ReloadableSource: source}
+		g = NewGraph(WithSources(NewLayeredSource(MapSource{"ratelimit": "1"}, counter)))
+		g.Provide(&ValueProvider{Value: &service})
+		Expect(g.Resolve()).To(Succeed())
+	})
+
+	It("Is injected for named values", func() {
+		Expect(service.RateLimit).ToNot(BeNil())
+		Expect(service.RateLimit.Name()).To(Equal("ratelimit"))
+		Expect(service.Timeout).To(BeNil())
+	})
+
+	It("Is shared by every lookup of a name", func() {
+		for i := 0; i < 100; i++ {
+			d, err := g.Find(reflect.TypeOf((*DynamicValue)(nil)), nil, "ratelimit")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d).To(BeIdenticalTo(service.RateLimit))
+		}
+		Expect(counter.listeners).To(Equal(1))
+	})
+
+	It("Decodes the current value", func() {
+		var limit int
+		Expect(service.RateLimit.Decode(&limit)).To(Succeed())
+		Expect(limit).To(Equal(10))
+
+		values = MapSource{"ratelimit": "20"}
+		Expect(source.Reload()).To(Succeed())
+		Expect(service.RateLimit.Decode(&limit)).To(Succeed())
+		Expect(limit).To(Equal(20))
+	})
+
+	It("Notifies subscribers of changes only", func() {
+		var changes []string
+		service.RateLimit.Subscribe(func(d *DynamicValue) {
+			var limit string
+			Expect(d.Decode(&limit)).To(Succeed())
+			changes = append(changes, limit)
+		})
+
+		Expect(source.Reload()).To(Succeed())
+		values = MapSource{"ratelimit": "20"}
+		Expect(source.Reload()).To(Succeed())
+		Expect(changes).To(Equal([]string{"20"}))
+	})
+
+	It("Falls back on lower layers when a value disappears", func() {
+		values = MapSource{}
+		Expect(source.Reload()).To(Succeed())
+
+		raw, ok := service.RateLimit.Raw()
+		Expect(ok).To(BeTrue())
+		Expect(raw).To(Equal("1"))
+	})
+
+	It("Reports values which cannot be decoded", func() {
+		var timeout time.Duration
+		Expect(service.RateLimit.Decode(&timeout)).ToNot(Succeed())
+		Expect(service.RateLimit.Decode(timeout)).ToNot(Succeed())
+	})
+})
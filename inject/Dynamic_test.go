@@ -0,0 +1,96 @@
+//go:build go1.18
+// +build go1.18
+
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/impinj/go-inject/inject"
+	"reflect"
+)
+
+var _ = Describe("Dynamic", func() {
+	type Service struct {
+		RateLimit *Dynamic[int]    `inject:"ratelimit"`
+		Raw       *DynamicValue    `inject:"ratelimit"`
+		Mode      *Dynamic[string] `inject:"mode,optional"`
+	}
+
+	var (
+		values  MapSource
+		source  *ReloadableSource
+		counter *countingSource
+		g       Graph
+		service Service
+	)
+
+	BeforeEach(func() {
+		values = MapSource{"ratelimit": "10"}
+
+		var err error
+		source, err = NewReloadableSource(func() (Source, error) {
+			return values, nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		service = Service{}
+		counter = &countingSource{ReloadableSource: source}
+		g = NewGraph(WithSources(counter))
+		g.Provide(&ValueProvider{Value: &service})
+		Expect(g.Resolve()).To(Succeed())
+	})
+
+	It("Is injected for named values", func() {
+		Expect(service.RateLimit).ToNot(BeNil())
+		Expect(service.RateLimit.Name()).To(Equal("ratelimit"))
+		Expect(service.Mode).To(BeNil())
+	})
+
+	It("Returns the current value", func() {
+		Expect(service.RateLimit.Get()).To(Equal(10))
+
+		values = MapSource{"ratelimit": "20"}
+		Expect(source.Reload()).To(Succeed())
+		Expect(service.RateLimit.Get()).To(Equal(20))
+	})
+
+	It("Notifies subscribers of changes only", func() {
+		var changes []int
+		service.RateLimit.Subscribe(func(limit int) {
+			changes = append(changes, limit)
+		})
+
+		Expect(source.Reload()).To(Succeed())
+		values = MapSource{"ratelimit": "20"}
+		Expect(source.Reload()).To(Succeed())
+		Expect(changes).To(Equal([]int{20}))
+	})
+
+	It("Keeps the last value which could be converted", func() {
+		values = MapSource{"ratelimit": "many"}
+		Expect(source.Reload()).To(Succeed())
+		Expect(service.RateLimit.Get()).To(Equal(10))
+
+		raw, _ := service.Raw.Raw()
+		Expect(raw).To(Equal("many"))
+	})
+
+	It("Is shared by every lookup of a name", func() {
+		for i := 0; i < 100; i++ {
+			d, err := g.Find(reflect.TypeOf((*Dynamic[int])(nil)), nil, "ratelimit")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d).To(BeIdenticalTo(service.RateLimit))
+		}
+		Expect(counter.listeners).To(Equal(1))
+	})
+
+	It("Fails for values which cannot be converted", func() {
+		values = MapSource{"ratelimit": "many"}
+		Expect(source.Reload()).To(Succeed())
+
+		_, err := g.Find(reflect.TypeOf((*Dynamic[float64])(nil)), nil, "ratelimit")
+		Expect(err).To(HaveOccurred())
+	})
+})
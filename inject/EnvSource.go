@@ -0,0 +1,49 @@
+package inject
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+// EnvSource supplies named values from environment variables. A name is
+// mapped to a variable by Transform, or EnvName if Transform is nil, and
+// then prefixed with Prefix, so "helloservice.url" is read from
+// HELLOSERVICE_URL.
+type EnvSource struct {
+	Prefix    string
+	Transform func(name string) string
+}
+
+// EnvName upper-cases name and replaces anything other than letters and
+// digits with underscores.
+func EnvName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, name)
+}
+
+func (s EnvSource) Key(name string) string {
+	return "environment variable " + s.variable(name)
+}
+
+func (s EnvSource) Lookup(name string) (interface{}, bool) {
+	if v, ok := os.LookupEnv(s.variable(name)); ok {
+		return v, true
+	}
+
+	return nil, false
+}
+
+func (s EnvSource) variable(name string) string {
+	transform := s.Transform
+	if transform == nil {
+		transform = EnvName
+	}
+
+	return s.Prefix + transform(name)
+}
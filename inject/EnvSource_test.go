@@ -0,0 +1,125 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"fmt"
+	. "github.com/impinj/go-inject/inject"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var _ = Describe("EnvSource", func() {
+	var (
+		source EnvSource
+		g      Graph
+	)
+
+	BeforeEach(func() {
+		source = EnvSource{Prefix: "INJECT_TEST_"}
+		g = NewGraph(WithSources(&source))
+	})
+
+	AfterEach(func() {
+		for _, env := range os.Environ() {
+			if strings.HasPrefix(env, "INJECT_TEST_") {
+				os.Unsetenv(strings.SplitN(env, "=", 2)[0])
+			}
+		}
+	})
+
+	DescribeTable("EnvName",
+		func(name, expected string) {
+			Expect(EnvName(name)).To(Equal(expected))
+		},
+		Entry("Dotted", "helloservice.url", "HELLOSERVICE_URL"),
+		Entry("Dashed", "rate-limit", "RATE_LIMIT"),
+		Entry("Mixed case", "maxConns", "MAXCONNS"),
+	)
+
+	Describe("Key", func() {
+		It("Names the environment variable", func() {
+			Expect(source.Key("helloservice.url")).To(Equal("environment variable INJECT_TEST_HELLOSERVICE_URL"))
+		})
+
+		It("Uses the transform", func() {
+			source.Transform = strings.ToLower
+			Expect(source.Key("Hello.URL")).To(Equal("environment variable INJECT_TEST_hello.url"))
+		})
+	})
+
+	Describe("Lookup", func() {
+		It("Returns set variables", func() {
+			os.Setenv("INJECT_TEST_HELLOSERVICE_URL", "https://www.example.org")
+			v, ok := source.Lookup("helloservice.url")
+			Expect(ok).To(BeTrue())
+			Expect(v).To(Equal("https://www.example.org"))
+		})
+
+		It("Reports unset variables", func() {
+			_, ok := source.Lookup("helloservice.url")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	DescribeTable("Finds named values of the requested type",
+		func(value string, expected interface{}) {
+			os.Setenv("INJECT_TEST_VALUE", value)
+			v, err := g.Find(reflect.TypeOf(expected), nil, "value")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(v).To(Equal(expected))
+		},
+		Entry("String", "hello", "hello"),
+		Entry("Int", "42", 42),
+		Entry("Uint8", "0x10", uint8(16)),
+		Entry("Float", "1.5", 1.5),
+		Entry("Bool", "true", true),
+		Entry("Duration", "1m30s", 90*time.Second),
+		Entry("Slice", "a, b,c", []string{"a", "b", "c"}),
+		Entry("Int slice", "1,2", []int{1, 2}),
+		Entry("Map", `{"a": 1}`, map[string]int{"a": 1}),
+	)
+
+	It("Completes named fields", func() {
+		type Service struct {
+			Url  string `inject:"helloservice.url"`
+			Port int    `inject:"helloservice.port,optional"`
+		}
+
+		os.Setenv("INJECT_TEST_HELLOSERVICE_URL", "https://www.example.org")
+
+		var s Service
+		Expect(g.Complete(&s)).To(Succeed())
+		Expect(s.Url).To(Equal("https://www.example.org"))
+		Expect(s.Port).To(BeZero())
+	})
+
+	It("Prefers providers", func() {
+		os.Setenv("INJECT_TEST_VALUE", "from env")
+		g.Provide(&ValueProvider{Name: "value", Value: "from provider"})
+
+		Expect(g.Find(reflect.TypeOf(""), nil, "value")).To(Equal("from provider"))
+	})
+
+	It("Reports the missing variable", func() {
+		_, err := g.Find(reflect.TypeOf(""), nil, "helloservice.url")
+		Expect(err).To(Equal(fmt.Errorf("Could not find provider for string named helloservice.url (checked environment variable INJECT_TEST_HELLOSERVICE_URL).")))
+	})
+
+	It("Reports values which cannot be converted", func() {
+		type Service struct {
+			Port int `inject:"helloservice.port,optional"`
+		}
+
+		os.Setenv("INJECT_TEST_HELLOSERVICE_PORT", "eighty")
+
+		var s Service
+		err := g.Complete(&s)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Could not convert environment variable INJECT_TEST_HELLOSERVICE_PORT to int"))
+	})
+})
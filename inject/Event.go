@@ -0,0 +1,83 @@
+package inject
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// EventKind identifies what happened in a graph.
+type EventKind int
+
+const (
+	// ProviderRegistered is sent for each provider given to Provide or
+	// Override, after result objects are expanded.
+	ProviderRegistered EventKind = iota
+
+	// LookupStarted is sent when the graph starts looking for a provider.
+	LookupStarted
+
+	// CandidatesByType, CandidatesByContext and CandidatesByName are sent
+	// as a lookup narrows the active providers down by each criterion in
+	// turn. Candidates holds the providers which remain.
+	CandidatesByType
+	CandidatesByContext
+	CandidatesByName
+
+	// ProviderChosen is sent when a lookup succeeds, including when the
+	// provider is backed by a source or bound just in time.
+	ProviderChosen
+
+	// BuilderInvoked is sent after a builder is called, with how long it
+	// took including the resolution of its parameters.
+	BuilderInvoked
+
+	// FieldSet is sent after an inject-tagged field is set.
+	FieldSet
+
+	// ResolutionFailed is sent when a lookup or a field fails. For a lookup,
+	// Candidates holds the matching providers: several if the request was
+	// ambiguous.
+	ResolutionFailed
+
+	// Completed is sent when a call to Complete returns, with how long it
+	// took including any nested completions, and its error if any.
+	Completed
+)
+
+var eventKindNames = []string{
+	"ProviderRegistered",
+	"LookupStarted",
+	"CandidatesByType",
+	"CandidatesByContext",
+	"CandidatesByName",
+	"ProviderChosen",
+	"BuilderInvoked",
+	"FieldSet",
+	"ResolutionFailed",
+	"Completed",
+}
+
+func (k EventKind) String() string {
+	if k < 0 || int(k) >= len(eventKindNames) {
+		return "EventKind(" + strconv.Itoa(int(k)) + ")"
+	}
+
+	return eventKindNames[k]
+}
+
+// Event describes something a graph did. Which fields are set depends on
+// the kind: Type, Context and Name describe a lookup, Owner and Field an
+// inject-tagged field.
+type Event struct {
+	Kind       EventKind
+	Type       reflect.Type
+	Context    reflect.Type
+	Name       string
+	Owner      reflect.Type
+	Field      string
+	Provider   Provider
+	Candidates []Provider
+	Duration   time.Duration
+	Err        error
+}
@@ -0,0 +1,125 @@
+package inject
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FlagSource supplies named values from the flags which were set on a
+// FlagSet, so -helloservice.url=... fills `inject:"helloservice.url"`.
+// Flags left at their defaults are not reported, allowing other sources to
+// supply those names.
+type FlagSource struct {
+	FlagSet *flag.FlagSet
+}
+
+func (s FlagSource) Key(name string) string {
+	return "flag -" + name
+}
+
+func (s FlagSource) Lookup(name string) (interface{}, bool) {
+	var (
+		value interface{}
+		found bool
+	)
+
+	s.FlagSet.Visit(func(f *flag.Flag) {
+		if found || !strings.EqualFold(f.Name, name) {
+			return
+		}
+
+		found = true
+		if getter, ok := f.Value.(flag.Getter); ok {
+			value = getter.Get()
+		} else {
+			value = f.Value.String()
+		}
+	})
+
+	return value, found
+}
+
+// RegisterFlags defines a flag on fs for every named injection point of a
+// primitive type in g which fs does not already define. The usage text of
+// each flag lists the fields it fills.
+func RegisterFlags(fs *flag.FlagSet, g Graph) {
+	points := map[string][]InjectionPoint{}
+	for _, point := range g.InjectionPoints() {
+		if point.Name != "" && isPrimitive(point.Type) {
+			points[point.Name] = append(points[point.Name], point)
+		}
+	}
+
+	var names []string
+	for name := range points {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if fs.Lookup(name) != nil {
+			continue
+		}
+
+		var fields []string
+		for _, point := range points[name] {
+			fields = append(fields, fmt.Sprintf("%s.%s", point.Owner, point.Field))
+		}
+
+		fs.Var(&flagValue{typeInfo: points[name][0].Type},
+			name,
+			fmt.Sprintf("%s injected into %s", points[name][0].Type, strings.Join(fields, ", ")))
+	}
+}
+
+func isPrimitive(typeInfo reflect.Type) bool {
+	switch typeInfo.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
+	}
+
+	return false
+}
+
+// flagValue is a flag.Value which parses its argument as a given type.
+type flagValue struct {
+	typeInfo reflect.Type
+	value    reflect.Value
+}
+
+func (v *flagValue) Get() interface{} {
+	if !v.value.IsValid() {
+		return reflect.Zero(v.typeInfo).Interface()
+	}
+
+	return v.value.Interface()
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.typeInfo.Kind() == reflect.Bool
+}
+
+func (v *flagValue) Set(s string) error {
+	parsed, err := parseString(s, v.typeInfo)
+	if err != nil {
+		return err
+	}
+
+	v.value = parsed
+	return nil
+}
+
+func (v *flagValue) String() string {
+	if v == nil || v.typeInfo == nil || !v.value.IsValid() {
+		return ""
+	}
+
+	return fmt.Sprint(v.value.Interface())
+}
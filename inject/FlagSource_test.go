@@ -0,0 +1,105 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"flag"
+	. "github.com/impinj/go-inject/inject"
+	"time"
+)
+
+var _ = Describe("FlagSource", func() {
+	type Service struct {
+		Url     string        `inject:"helloservice.url"`
+		Port    int           `inject:"helloservice.port,optional"`
+		Timeout time.Duration `inject:"helloservice.timeout,optional"`
+		Debug   bool          `inject:"debug,optional"`
+		Tags    []string      `inject:"helloservice.tags,optional"`
+		A       InterfaceA    `inject:",optional"`
+	}
+
+	var (
+		fs      *flag.FlagSet
+		g       Graph
+		service Service
+	)
+
+	BeforeEach(func() {
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+		service = Service{}
+
+		g = NewGraph(WithSources(FlagSource{FlagSet: fs}))
+		g.Provide(&ValueProvider{Value: &service})
+	})
+
+	Describe("Lookup", func() {
+		BeforeEach(func() {
+			fs.String("helloservice.url", "https://www.example.org/default", "")
+		})
+
+		It("Returns flags which were set", func() {
+			Expect(fs.Parse([]string{"-helloservice.url=https://www.example.org"})).To(Succeed())
+
+			v, ok := FlagSource{FlagSet: fs}.Lookup("helloservice.url")
+			Expect(ok).To(BeTrue())
+			Expect(v).To(Equal("https://www.example.org"))
+		})
+
+		It("Ignores flags left at their defaults", func() {
+			Expect(fs.Parse(nil)).To(Succeed())
+
+			_, ok := FlagSource{FlagSet: fs}.Lookup("helloservice.url")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("RegisterFlags", func() {
+		BeforeEach(func() {
+			RegisterFlags(fs, g)
+		})
+
+		It("Defines a flag for every named primitive injection", func() {
+			for _, name := range []string{"helloservice.url", "helloservice.port", "helloservice.timeout", "debug"} {
+				Expect(fs.Lookup(name)).ToNot(BeNil(), name)
+			}
+
+			Expect(fs.Lookup("helloservice.tags")).To(BeNil())
+		})
+
+		It("Describes the injected fields in the usage text", func() {
+			Expect(fs.Lookup("helloservice.url").Usage).To(Equal("string injected into inject_test.Service.Url"))
+		})
+
+		It("Fills the fields from the parsed flags", func() {
+			Expect(fs.Parse([]string{
+				"-helloservice.url=https://www.example.org",
+				"-helloservice.port", "8080",
+				"-helloservice.timeout=5s",
+				"-debug",
+			})).To(Succeed())
+
+			Expect(g.Resolve()).To(Succeed())
+			Expect(service).To(Equal(Service{
+				Url:     "https://www.example.org",
+				Port:    8080,
+				Timeout: 5 * time.Second,
+				Debug:   true,
+			}))
+		})
+
+		It("Rejects flags of the wrong type", func() {
+			Expect(fs.Parse([]string{"-helloservice.port=eighty"})).ToNot(Succeed())
+		})
+
+		It("Does not redefine existing flags", func() {
+			fs = flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.String("helloservice.url", "https://www.example.org/default", "The service URL.")
+			RegisterFlags(fs, g)
+
+			Expect(fs.Lookup("helloservice.url").Usage).To(Equal("The service URL."))
+		})
+	})
+})
@@ -2,33 +2,77 @@ package inject
 
 import (
 	"fmt"
+	"io"
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 )
 
-const injectTag string = "inject"
+const (
+	injectTag      string = "inject"
+	optionalOption string = "optional"
+	secretOption   string = "secret"
+)
 
 type Graph interface {
 	Complete(v interface{}) error
+	Describe() Description
 	Find(typeInfo, context reflect.Type, name string) (interface{}, error)
+	InjectionPoints() []InjectionPoint
+	Origin(typeInfo, context reflect.Type, name string) (string, bool)
+	Override(providers ...Provider) error
 	Provide(providers ...Provider)
+	Provides(typeInfo, context reflect.Type, name string) bool
 	Resolve() error
+	WriteDot(w io.Writer) error
 }
 
-func NewGraph() Graph {
-	return &graph{}
+func NewGraph(options ...Option) Graph {
+	g := &graph{}
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
 }
 
 type graph struct {
-	providers []Provider
+	mode            Mode
+	profiles        []string
+	providers       []Provider
+	activeProviders []Provider
+	sources         []Source
+	dynamics        map[dynamicKey]interface{}
+	completed       map[reflect.Type]map[string]supply
+	completedTypes  []reflect.Type
+	listeners       []Listener
+	traceRegions    bool
 }
 
 func (g *graph) Provide(providers ...Provider) {
-	g.providers = append(g.providers, providers...)
+	expanded := expandResultObjects(providers)
+	g.providers = append(g.providers, expanded...)
+	g.activeProviders = nil
+
+	for _, provider := range expanded {
+		g.emit(Event{Kind: ProviderRegistered, Type: providerType(provider), Provider: provider})
+	}
+}
+
+func (g *graph) Complete(v interface{}) error {
+	if len(g.listeners) == 0 {
+		return g.complete(v)
+	}
+
+	start := time.Now()
+	err := g.complete(v)
+	g.emit(Event{Kind: Completed, Type: reflect.TypeOf(v), Duration: time.Since(start), Err: err})
+
+	return err
 }
 
-func (g graph) Complete(v interface{}) error {
+func (g *graph) complete(v interface{}) error {
 	switch k := reflect.TypeOf(v).Kind(); k {
 	case reflect.Ptr:
 
@@ -42,7 +86,7 @@ func (g graph) Complete(v interface{}) error {
 		q = q[1:]
 
 		el := deferenceValue(reflect.ValueOf(c))
-		providersByType := selectProvidersByType(g.providers, el.Type())
+		providersByType := selectProvidersByType(g.active(), el.Type())
 		for _, provider := range providersByType {
 			if provider.IsComplete() {
 				reflect.ValueOf(v).Elem().Set(reflect.ValueOf(provider.Resolve()))
@@ -50,7 +94,7 @@ func (g graph) Complete(v interface{}) error {
 			}
 		}
 
-		if err := completeHelper(g.providers, el); err != nil {
+		if err := g.completeHelper(el); err != nil {
 			return err
 		}
 
@@ -64,19 +108,35 @@ func (g graph) Complete(v interface{}) error {
 	return nil
 }
 
-func (g graph) Find(typeInfo, context reflect.Type, name string) (interface{}, error) {
-	if provider, err := findHelper(g.providers, typeInfo, context, name); err != nil {
+func (g *graph) Find(typeInfo, context reflect.Type, name string) (interface{}, error) {
+	if provider, err := g.find(typeInfo, context, injectOptions{name: name}); err != nil {
 		return nil, err
 	} else {
-		return provider.Resolve(), nil
+		return g.resolve(provider)
 	}
 }
 
-func (g graph) Resolve() error {
+func (g *graph) Resolve() error {
 	var errs []error
 
-	for _, provider := range g.providers {
-		if valProv, ok := provider.(*ValueProvider); ok &&
+	g.activeProviders = nil
+
+	if err := g.validateSources(); err != nil {
+		return err
+	}
+
+	for _, provider := range g.active() {
+		if asProvider, ok := provider.(AsProvider); ok {
+			for _, as := range asProvider.GetAs() {
+				if !provider.GetType().AssignableTo(as) {
+					errs = append(errs, fmt.Errorf("Provider of type %s cannot be provided as %s.",
+						provider.GetType(),
+						as))
+				}
+			}
+		}
+
+		if valProv := valueProviderOf(provider); valProv != nil &&
 			valProv.Value != nil &&
 			!provider.IsComplete() {
 			switch reflect.ValueOf(provider.Resolve()).Kind() {
@@ -98,28 +158,115 @@ func (g graph) Resolve() error {
 	return nil
 }
 
-func completeHelper(providers []Provider, el reflect.Value) error {
+func (g *graph) completeHelper(el reflect.Value) error {
 	for i, field := range selectInjectableFields(el) {
 		fieldInfo := el.Type().Field(i)
-		name := fieldInfo.Tag.Get(injectTag)
+		options := parseInjectTag(fieldInfo.Tag.Get(injectTag))
 
 		if !field.CanSet() {
-			return fmt.Errorf("Cannot set a field (%s) on a non-struct object (%s).", fieldInfo.Name, el.Type())
+			err := fmt.Errorf("Cannot set a field (%s) on a non-struct object (%s).", fieldInfo.Name, el.Type())
+			g.emit(Event{Kind: ResolutionFailed, Owner: el.Type(), Field: fieldInfo.Name, Err: err})
+			return err
+		}
+
+		provider, err := g.find(field.Type(), el.Type(), options)
+		if err != nil && options.optional && !g.Provides(field.Type(), el.Type(), options.name) {
+			continue
 		}
 
-		if provider, err := findHelper(providers, field.Type(), el.Type(), name); err != nil {
+		var v interface{}
+		if err == nil {
+			v, err = g.resolve(provider)
+		}
+
+		if err != nil {
 			return fmt.Errorf("Encountered error attempting to set a field (%s) of type %s: %s",
 				fieldInfo.Name,
 				fieldInfo.Type,
 				err)
 		} else {
-			field.Set(reflect.ValueOf(provider.Resolve()))
+			field.Set(reflect.ValueOf(v))
+			g.recordCompletion(el.Type(), fieldInfo.Name, options.name, provider)
+			g.emit(Event{
+				Kind:     FieldSet,
+				Type:     field.Type(),
+				Context:  el.Type(),
+				Name:     options.name,
+				Owner:    el.Type(),
+				Field:    fieldInfo.Name,
+				Provider: provider,
+			})
 		}
 	}
 
 	return nil
 }
 
+// find selects the provider for a type, reporting the lookup to the
+// graph's listeners.
+func (g *graph) find(typeInfo, context reflect.Type, options injectOptions) (Provider, error) {
+	name := options.name
+	event := Event{Type: typeInfo, Context: context, Name: name}
+	if len(g.listeners) > 0 {
+		event.Kind = LookupStarted
+		g.emit(event)
+		g.traceCandidates(typeInfo, context, name)
+	}
+
+	provider, err := g.lookup(typeInfo, context, options)
+	if err != nil {
+		event.Kind, event.Err = ResolutionFailed, err
+		if len(g.listeners) > 0 {
+			event.Candidates = selectProviders(g.active(), typeInfo, context, name)
+		}
+	} else {
+		event.Kind, event.Provider = ProviderChosen, provider
+	}
+	g.emit(event)
+
+	return provider, err
+}
+
+// lookup selects the provider for a type, falling back on the graph's
+// sources for named requests and on a just-in-time binding when the graph
+// allows it.
+func (g *graph) lookup(typeInfo, context reflect.Type, options injectOptions) (Provider, error) {
+	name := options.name
+	provider, err := findHelper(g.active(), typeInfo, context, name)
+	if err == nil || len(selectProviders(g.active(), typeInfo, context, name)) > 0 {
+		return provider, err
+	}
+
+	if name != "" && len(g.sources) > 0 {
+		return g.findInSources(typeInfo, name, options.secret)
+	}
+
+	if g.mode != JustInTime ||
+		name != "" ||
+		typeInfo.Kind() != reflect.Ptr ||
+		typeInfo.Elem().Kind() != reflect.Struct ||
+		len(selectProvidersByType(g.active(), typeInfo)) > 0 {
+		return provider, err
+	}
+
+	provider = &TypeProvider{
+		Type:           typeInfo,
+		Scope:          Singleton,
+		ResolveContext: g,
+	}
+	g.providers = append(g.providers, provider)
+	g.activeProviders = nil
+
+	return provider, nil
+}
+
+// Provides reports whether the graph has a provider or source for a
+// request, even if it cannot be resolved unambiguously. Nothing is resolved.
+func (g *graph) Provides(typeInfo, context reflect.Type, name string) bool {
+	return len(selectProviders(g.active(), typeInfo, context, name)) > 0 ||
+		(name != "" && g.inSources(name))
+}
+
 func deferenceValue(el reflect.Value) reflect.Value {
 	for {
 		switch el.Kind() {
@@ -136,9 +283,7 @@ func deferenceValue(el reflect.Value) reflect.Value {
 }
 
 func findHelper(providers []Provider, typeInfo, context reflect.Type, name string) (Provider, error) {
-	providersForType := selectProvidersByType(providers, typeInfo)
-	providersForType = selectProvidersByContext(providersForType, context)
-	providersForType = selectProvidersByName(providersForType, name)
+	providersForType := selectProviders(providers, typeInfo, context, name)
 
 	switch len(providersForType) {
 	case 0:
@@ -178,6 +323,32 @@ func isComplete(v reflect.Value) bool {
 	return true
 }
 
+// injectOptions holds the parsed form of an inject tag, e.g.
+// `inject:"helloservice.url,optional"`. Secret values are redacted from
+// the errors reported when they cannot be converted.
+type injectOptions struct {
+	name     string
+	optional bool
+	secret   bool
+}
+
+func parseInjectTag(tag string) injectOptions {
+	parts := strings.Split(tag, ",")
+
+	options := injectOptions{name: parts[0]}
+	for _, option := range parts[1:] {
+		switch strings.TrimSpace(option) {
+		case optionalOption:
+			options.optional = true
+
+		case secretOption:
+			options.secret = true
+		}
+	}
+
+	return options
+}
+
 func selectInjectableFields(v reflect.Value) map[int]reflect.Value {
 	injectableFields := map[int]reflect.Value{}
 	typeInfo := v.Type()
@@ -191,6 +362,12 @@ func selectInjectableFields(v reflect.Value) map[int]reflect.Value {
 	return injectableFields
 }
 
+func selectProviders(providers []Provider, typeInfo, context reflect.Type, name string) []Provider {
+	providersForType := selectProvidersByType(providers, typeInfo)
+	providersForType = selectProvidersByContext(providersForType, context)
+	return selectProvidersByName(providersForType, name)
+}
+
 func selectProvidersByContext(providers []Provider, context reflect.Type) []Provider {
 	if context == nil {
 		return providers
@@ -228,7 +405,7 @@ func selectProvidersByName(providers []Provider, name string) []Provider {
 func selectProvidersByType(providers []Provider, typeInfo reflect.Type) []Provider {
 	var providersForType []Provider
 	for _, candidate := range providers {
-		if candidate.GetType().AssignableTo(typeInfo) {
+		if providesType(candidate, typeInfo) {
 			providersForType = append(providersForType, candidate)
 		}
 	}
@@ -236,6 +413,20 @@ func selectProvidersByType(providers []Provider, typeInfo reflect.Type) []Provid
 	return providersForType
 }
 
+func providesType(provider Provider, typeInfo reflect.Type) bool {
+	if asProvider, ok := provider.(AsProvider); ok && len(asProvider.GetAs()) > 0 {
+		for _, as := range asProvider.GetAs() {
+			if as.AssignableTo(typeInfo) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return provider.GetType().AssignableTo(typeInfo)
+}
+
 func structTagLookup(structTag reflect.StructTag, key string) (string, bool) {
 	tags := strings.Split((string)(structTag), " ")
 
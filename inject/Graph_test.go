@@ -5,8 +5,10 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	"bytes"
 	"fmt"
 	. "github.com/impinj/go-inject/inject"
+	"io"
 	"reflect"
 )
 
@@ -212,6 +214,113 @@ var _ = Describe("Graph", func() {
 			})
 		})
 
+		Context("Graph with optional injections", func() {
+			type Struct struct {
+				X int        `inject:"ValA,optional"`
+				Y InterfaceA `inject:",optional"`
+				Z string     `inject:"ValB"`
+			}
+
+			var v Struct
+
+			BeforeEach(func() {
+				v = Struct{}
+				g.Provide(
+					&ValueProvider{
+						Name:  "ValB",
+						Value: "b",
+					},
+				)
+			})
+
+			It("Leaves missing optional fields untouched", func() {
+				Expect(g.Complete(&v)).To(Succeed())
+				Expect(v.X).To(BeZero())
+				Expect(v.Y).To(BeNil())
+				Expect(v.Z).To(Equal("b"))
+			})
+
+			It("Still fills optional fields that have a provider", func() {
+				g.Provide(
+					&ValueProvider{
+						Name:  "ValA",
+						Value: 5,
+					},
+				)
+
+				Expect(g.Complete(&v)).To(Succeed())
+				Expect(v.X).To(Equal(5))
+			})
+
+			It("Still fails when an optional field is ambiguous", func() {
+				g.Provide(
+					&ValueProvider{Value: &PtrDecorated{}},
+					&ValueProvider{Value: &PtrDecorated{}},
+				)
+
+				Expect(g.Complete(&v)).ToNot(Succeed())
+			})
+		})
+
+		Context("Graph with providers restricted by type", func() {
+			type Struct struct {
+				A InterfaceA `inject:""`
+			}
+
+			var (
+				v Struct
+				a CustomA
+			)
+
+			BeforeEach(func() {
+				v = Struct{}
+				g.Provide(
+					&ValueProvider{
+						Value: &a,
+						As:    []reflect.Type{reflect.TypeOf((*InterfaceA)(nil)).Elem()},
+					},
+				)
+			})
+
+			It("Resolves the declared types", func() {
+				Expect(g.Complete(&v)).To(Succeed())
+				Expect(v.A).To(BeIdenticalTo(&a))
+			})
+
+			It("Does not resolve the concrete type", func() {
+				_, err := g.Find(reflect.TypeOf(&a), nil, "")
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("Removes the ambiguity with unrestricted providers", func() {
+				g.Provide(
+					&ValueProvider{Value: &CustomA{Val: 1}},
+				)
+
+				found, err := g.Find(reflect.TypeOf(&a), nil, "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).ToNot(BeIdenticalTo(&a))
+			})
+
+			It("Honors the type set of a wrapped provider", func() {
+				g = NewGraph()
+				g.Provide(
+					&SingletonProvider{
+						Provider: &BuilderProvider{
+							Builder: func() *CustomA {
+								return &a
+							},
+							As: []reflect.Type{reflect.TypeOf((*InterfaceA)(nil)).Elem()},
+						},
+					},
+				)
+
+				Expect(g.Complete(&v)).To(Succeed())
+				_, err := g.Find(reflect.TypeOf(&a), nil, "")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
 		Context("Graph with context selected injections", func() {
 			Context("Struct type context", func() {
 				var (
@@ -457,6 +566,457 @@ var _ = Describe("Graph", func() {
 		})
 	})
 
+	Describe("Builder with a parameter object", func() {
+		type Params struct {
+			In
+
+			Url     string     `inject:"helloservice.url"`
+			Timeout int        `inject:"helloservice.timeout,optional"`
+			Service InterfaceA `inject:""`
+		}
+
+		type Built struct {
+			Params Params
+		}
+
+		var decorated PtrDecorated
+
+		BeforeEach(func() {
+			g.Provide(
+				&ValueProvider{
+					Name:  "helloservice.url",
+					Value: "https://www.example.org/hello",
+				},
+				&ValueProvider{
+					Value: &PtrDecorated{},
+				},
+				&ValueProvider{
+					Context: reflect.TypeOf(Params{}),
+					Value:   &decorated,
+				},
+				&BuilderProvider{
+					Builder: func(params Params) *Built {
+						return &Built{Params: params}
+					},
+					ResolveContext: g,
+				},
+			)
+		})
+
+		It("Resolves each field with names, contexts and options", func() {
+			v, err := g.Find(reflect.TypeOf((*Built)(nil)), nil, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(v.(*Built).Params.Url).To(Equal("https://www.example.org/hello"))
+			Expect(v.(*Built).Params.Timeout).To(BeZero())
+			Expect(v.(*Built).Params.Service).To(BeIdenticalTo(&decorated))
+		})
+
+		It("Fails when an optional field is ambiguous", func() {
+			type Optional struct {
+				In
+
+				A InterfaceA `inject:",optional"`
+			}
+
+			g.Provide(
+				&ValueProvider{Value: CustomA{Val: 1}},
+				&ValueProvider{Value: CustomA{Val: 2}},
+				&BuilderProvider{
+					Builder: func(params Optional) *Optional {
+						return &params
+					},
+					ResolveContext: g,
+				})
+
+			_, err := g.Find(reflect.TypeOf((*Optional)(nil)), nil, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Builder with a result object", func() {
+		type Pool struct{ Size int }
+		type HealthCheck struct{ Pool *Pool }
+
+		type Result struct {
+			Out
+
+			Pool    *Pool
+			Health  *HealthCheck
+			Metrics string `inject:"db.metrics"`
+			ignored int
+		}
+
+		var invocations int
+
+		BeforeEach(func() {
+			invocations = 0
+			g.Provide(
+				&BuilderProvider{
+					Builder: func() Result {
+						invocations++
+						pool := &Pool{Size: 10}
+						return Result{
+							Pool:    pool,
+							Health:  &HealthCheck{Pool: pool},
+							Metrics: "db_pool_size",
+						}
+					},
+					ResolveContext: g,
+				},
+			)
+		})
+
+		It("Provides each field separately", func() {
+			type Service struct {
+				Pool    *Pool        `inject:""`
+				Health  *HealthCheck `inject:""`
+				Metrics string       `inject:"db.metrics"`
+			}
+
+			var s Service
+			Expect(g.Complete(&s)).To(Succeed())
+			Expect(s.Pool.Size).To(Equal(10))
+			Expect(s.Health.Pool).To(BeIdenticalTo(s.Pool))
+			Expect(s.Metrics).To(Equal("db_pool_size"))
+		})
+
+		It("Invokes the builder once for all fields", func() {
+			_, err := g.Find(reflect.TypeOf((*Pool)(nil)), nil, "")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = g.Find(reflect.TypeOf((*HealthCheck)(nil)), nil, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invocations).To(Equal(1))
+		})
+
+		It("Provides the result object from the same invocation", func() {
+			pool, err := g.Find(reflect.TypeOf((*Pool)(nil)), nil, "")
+			Expect(err).ToNot(HaveOccurred())
+			result, err := g.Find(reflect.TypeOf(Result{}), nil, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(Result).Pool).To(BeIdenticalTo(pool))
+			Expect(invocations).To(Equal(1))
+		})
+
+		It("Does not provide unexported fields", func() {
+			_, err := g.Find(reflect.TypeOf(0), nil, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Interface bound to an implementation type", func() {
+		type Service struct {
+			A InterfaceA `inject:""`
+		}
+
+		It("Constructs and completes the implementation", func() {
+			type Consumer struct {
+				S ServiceInterface `inject:""`
+			}
+
+			a := CustomA{Val: 10}
+			g.Provide(
+				&ValueProvider{Value: &a},
+				Bind(g,
+					reflect.TypeOf((*ServiceInterface)(nil)).Elem(),
+					reflect.TypeOf((*ServicePtrImpl)(nil))),
+			)
+
+			var c Consumer
+			Expect(g.Complete(&c)).To(Succeed())
+			Expect(c.S).To(BeAssignableToTypeOf(&ServicePtrImpl{}))
+			Expect(c.S.(*ServicePtrImpl).X).To(BeIdenticalTo(&a))
+		})
+
+		It("Resolves cycles through singletons", func() {
+			a := Bind(g,
+				reflect.TypeOf((*InterfaceA)(nil)).Elem(),
+				reflect.TypeOf((*PtrImplA)(nil)))
+			a.Scope = Singleton
+			b := Bind(g,
+				reflect.TypeOf((*InterfaceB)(nil)).Elem(),
+				reflect.TypeOf((*PtrImplB)(nil)))
+			b.Scope = Singleton
+			g.Provide(a, b)
+
+			var s Service
+			Expect(g.Complete(&s)).To(Succeed())
+			Expect(s.A.(*PtrImplA).B.(*PtrImplB).A).To(BeIdenticalTo(s.A))
+		})
+
+		It("Reports cycles through transient bindings", func() {
+			g.Provide(
+				Bind(g,
+					reflect.TypeOf((*InterfaceA)(nil)).Elem(),
+					reflect.TypeOf((*PtrImplA)(nil))),
+				Bind(g,
+					reflect.TypeOf((*InterfaceB)(nil)).Elem(),
+					reflect.TypeOf((*PtrImplB)(nil))),
+			)
+
+			var s Service
+			err := g.Complete(&s)
+			Expect(err).To(MatchError(ContainSubstring("Found a dependency cycle through *inject_test.PtrImplA.")))
+		})
+
+		It("Returns the cause when the implementation cannot be completed", func() {
+			type Consumer struct {
+				S ServiceInterface `inject:""`
+			}
+
+			g.Provide(
+				Bind(g,
+					reflect.TypeOf((*ServiceInterface)(nil)).Elem(),
+					reflect.TypeOf((*ServicePtrImpl)(nil))),
+			)
+
+			var c Consumer
+			err := g.Complete(&c)
+			Expect(err).To(MatchError(ContainSubstring("Could not find provider for inject_test.InterfaceA.")))
+		})
+	})
+
+	Describe("Just-in-time bindings", func() {
+		type Leaf struct {
+			Val int `inject:"leaf.val"`
+		}
+
+		type Service struct {
+			Leaf *Leaf `inject:""`
+		}
+
+		type Consumer struct {
+			Service *Service `inject:""`
+			Leaf    *Leaf    `inject:""`
+		}
+
+		Context("In strict mode", func() {
+			It("Returns an error for unprovided types", func() {
+				var c Consumer
+				Expect(g.Complete(&c)).ToNot(Succeed())
+			})
+		})
+
+		Context("In just-in-time mode", func() {
+			BeforeEach(func() {
+				g = NewGraph(WithMode(JustInTime))
+				g.Provide(
+					&ValueProvider{
+						Name:  "leaf.val",
+						Value: 5,
+					},
+				)
+			})
+
+			It("Constructs and completes unprovided struct pointers", func() {
+				var c Consumer
+				Expect(g.Complete(&c)).To(Succeed())
+				Expect(c.Leaf.Val).To(Equal(5))
+				Expect(c.Service.Leaf).To(BeIdenticalTo(c.Leaf))
+			})
+
+			It("Shares the constructed values", func() {
+				v1, err := g.Find(reflect.TypeOf((*Service)(nil)), nil, "")
+				Expect(err).ToNot(HaveOccurred())
+				v2, err := g.Find(reflect.TypeOf((*Service)(nil)), nil, "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(v1).To(BeIdenticalTo(v2))
+			})
+
+			DescribeTable("Does not construct other requests",
+				func(typeInfo reflect.Type, name string) {
+					_, err := g.Find(typeInfo, nil, name)
+					Expect(err).To(HaveOccurred())
+				},
+				Entry("Named", reflect.TypeOf((*Service)(nil)), "service"),
+				Entry("Interface", reflect.TypeOf((*InterfaceA)(nil)).Elem(), ""),
+				Entry("Struct value", reflect.TypeOf(Service{}), ""),
+			)
+
+			It("Does not shadow providers excluded by context", func() {
+				g.Provide(
+					&ValueProvider{
+						Context: reflect.TypeOf(Consumer{}),
+						Value:   &Leaf{},
+					},
+				)
+
+				_, err := g.Find(reflect.TypeOf((*Leaf)(nil)), reflect.TypeOf(Service{}), "")
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("Returns the cause when a constructed value cannot be completed", func() {
+				type Missing struct {
+					Val string `inject:"missing.val"`
+				}
+
+				type Needs struct {
+					M *Missing `inject:""`
+				}
+
+				var n Needs
+				err := g.Complete(&n)
+				Expect(err).To(MatchError(ContainSubstring("Could not find provider for string.")))
+			})
+		})
+	})
+
+	Describe("InjectionPoints", func() {
+		type Leaf struct {
+			Url string `inject:"helloservice.url"`
+		}
+
+		type Params struct {
+			In
+
+			Port int `inject:"helloservice.port,optional"`
+		}
+
+		type Root struct {
+			Leaf  *Leaf `inject:""`
+			Other int
+		}
+
+		BeforeEach(func() {
+			g.Provide(
+				&ValueProvider{Value: &Root{}},
+				&BuilderProvider{
+					Builder: func(Params) InterfaceA { return nil },
+				},
+				&SingletonProvider{},
+			)
+		})
+
+		It("Lists the fields of provided structs, builder parameters and their dependencies", func() {
+			Expect(g.InjectionPoints()).To(ConsistOf(
+				InjectionPoint{
+					Owner: reflect.TypeOf(Root{}),
+					Field: "Leaf",
+					Type:  reflect.TypeOf((*Leaf)(nil)),
+				},
+				InjectionPoint{
+					Owner: reflect.TypeOf(Leaf{}),
+					Field: "Url",
+					Type:  reflect.TypeOf(""),
+					Name:  "helloservice.url",
+				},
+				InjectionPoint{
+					Owner:    reflect.TypeOf(Params{}),
+					Field:    "Port",
+					Type:     reflect.TypeOf(0),
+					Name:     "helloservice.port",
+					Optional: true,
+				},
+			))
+		})
+	})
+
+	Describe("Override", func() {
+		var (
+			real, fake CustomA
+			interfaceA reflect.Type
+		)
+
+		BeforeEach(func() {
+			real = CustomA{Val: 1}
+			fake = CustomA{Val: 2}
+			interfaceA = reflect.TypeOf((*InterfaceA)(nil)).Elem()
+
+			g.Provide(
+				&ValueProvider{Name: "helloservice.url", Value: "https://www.example.org"},
+				&BuilderProvider{
+					Builder: func() InterfaceA {
+						return &real
+					},
+				},
+			)
+		})
+
+		It("Replaces bindings of the same type and name", func() {
+			Expect(g.Override(
+				&ValueProvider{Name: "helloservice.url", Value: "http://localhost"},
+			)).To(Succeed())
+
+			Expect(g.Find(reflect.TypeOf(""), nil, "helloservice.url")).To(Equal("http://localhost"))
+		})
+
+		It("Replaces interface bindings with implementations bound as the interface", func() {
+			Expect(g.Override(&ValueProvider{Value: &fake, As: []reflect.Type{interfaceA}})).To(Succeed())
+
+			Expect(g.Find(interfaceA, nil, "")).To(BeIdenticalTo(&fake))
+		})
+
+		It("Replaces bindings within their context only", func() {
+			g.Provide(&ValueProvider{Context: reflect.TypeOf(Decorator{}), Value: &real})
+			Expect(g.Override(&ValueProvider{Context: reflect.TypeOf(Decorator{}), Value: &fake})).To(Succeed())
+
+			Expect(g.Find(interfaceA, reflect.TypeOf(Decorator{}), "")).To(BeIdenticalTo(&fake))
+			Expect(g.Find(interfaceA, reflect.TypeOf(ServiceValueImpl{}), "")).To(BeIdenticalTo(&real))
+		})
+
+		It("Fails for bindings which do not exist and changes nothing", func() {
+			err := g.Override(
+				&ValueProvider{Value: &fake, As: []reflect.Type{interfaceA}},
+				&ValueProvider{Name: "helloservice.token", Value: "secret"},
+			)
+			Expect(err).To(Equal(fmt.Errorf("Could not find bindings to override for %s.",
+				"type: [string], context: %!s(<nil>), name: helloservice.token")))
+
+			Expect(g.Find(interfaceA, nil, "")).To(BeIdenticalTo(&real))
+		})
+
+		It("Keeps bindings of other types which the override also satisfies", func() {
+			g = NewGraph()
+			g.Provide(
+				&ValueProvider{Value: &bytes.Buffer{}},
+				&BuilderProvider{
+					Builder: func() io.Writer {
+						return &bytes.Buffer{}
+					},
+				},
+			)
+
+			fake := &bytes.Buffer{}
+			Expect(g.Override(&ValueProvider{Value: fake})).To(Succeed())
+
+			Expect(g.Find(reflect.TypeOf(fake), nil, "")).To(BeIdenticalTo(fake))
+			providers := g.Describe().Providers
+			Expect(providers).To(HaveLen(2))
+			Expect(providers[1].Kind).To(Equal("BuilderProvider"))
+			Expect(providers[1].Type).To(Equal("io.Writer"))
+		})
+
+		It("Fails when two overrides replace the same binding and changes nothing", func() {
+			g.Provide(&ValueProvider{Value: &real})
+			err := g.Override(&ValueProvider{Value: &fake}, &ValueProvider{Value: &CustomA{}})
+			Expect(err).To(MatchError(ContainSubstring("Found multiple overrides for type: [*inject_test.CustomA]")))
+
+			Expect(g.Find(reflect.TypeOf(&real), nil, "")).To(BeIdenticalTo(&real))
+		})
+
+		It("Replaces every conditional variant of a binding", func() {
+			dev := CustomA{Val: 3}
+			g = NewGraph(WithProfiles("prod"))
+			g.Provide(
+				&ConditionalProvider{
+					Provider: &ValueProvider{Value: &dev},
+					When:     []Condition{OnProfile("dev")},
+				},
+				&ConditionalProvider{
+					Provider: &ValueProvider{Value: &real},
+					When:     []Condition{OnProfile("prod")},
+				},
+			)
+			Expect(g.Override(&ValueProvider{Value: &fake})).To(Succeed())
+
+			Expect(g.Find(interfaceA, nil, "")).To(BeIdenticalTo(&fake))
+			Expect(g.Describe().Providers).To(HaveLen(1))
+		})
+
+		It("Does not replace bindings the override cannot stand in for", func() {
+			Expect(g.Override(&ValueProvider{Name: "helloservice.url", Value: 8080})).ToNot(Succeed())
+		})
+	})
+
 	Describe("Find", func() {
 		var (
 			typeInfo, context reflect.Type
@@ -591,6 +1151,23 @@ var _ = Describe("Graph", func() {
 			})
 		})
 
+		Context("A provider declares a type it does not implement", func() {
+			BeforeEach(func() {
+				g.Provide(
+					&ValueProvider{
+						Value: &StructA{B: &StructB{}},
+						As:    []reflect.Type{reflect.TypeOf((*InterfaceA)(nil)).Elem()},
+					},
+				)
+			})
+
+			It("Returns an error", func() {
+				Expect(g.Resolve()).To(Equal(fmt.Errorf("Provider of type %s cannot be provided as %s.",
+					reflect.TypeOf((*StructA)(nil)),
+					reflect.TypeOf((*InterfaceA)(nil)).Elem())))
+			})
+		})
+
 		Context("Graph with context selected injections", func() {
 			var (
 				b Decorated
@@ -0,0 +1,29 @@
+package inject
+
+import "reflect"
+
+// In marks a struct as a parameter object. When a builder accepts a struct
+// embedding In, each of its inject-tagged fields is resolved separately,
+// honoring names and options exactly as struct injection does.
+type In struct{}
+
+var inType = reflect.TypeOf(In{})
+
+func isParamObject(typeInfo reflect.Type) bool {
+	return embeds(typeInfo, inType)
+}
+
+func embeds(typeInfo, marker reflect.Type) bool {
+	if typeInfo.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < typeInfo.NumField(); i++ {
+		fieldInfo := typeInfo.Field(i)
+		if fieldInfo.Anonymous && fieldInfo.Type == marker {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package inject
+
+import "reflect"
+
+// InjectionPoint describes an inject-tagged field which the graph may be
+// asked to fill.
+type InjectionPoint struct {
+	Owner    reflect.Type
+	Field    string
+	Type     reflect.Type
+	Name     string
+	Optional bool
+	Secret   bool
+}
+
+// InjectionPoints returns the inject-tagged fields of every struct reachable
+// from the graph's providers: provided structs, the parameters of builders
+// and, transitively, the structs referenced by those fields.
+func (g *graph) InjectionPoints() []InjectionPoint {
+	var points []InjectionPoint
+	visited := map[reflect.Type]bool{}
+
+	for _, provider := range g.active() {
+		if builder := builderOf(provider); builder != nil {
+			typeInfo := reflect.TypeOf(builder.Builder)
+			if typeInfo != nil && typeInfo.Kind() == reflect.Func {
+				for i := 0; i < typeInfo.NumIn(); i++ {
+					points = appendInjectionPoints(points, typeInfo.In(i), visited)
+				}
+			}
+		}
+
+		if typeInfo := providerType(provider); typeInfo != nil {
+			points = appendInjectionPoints(points, typeInfo, visited)
+		}
+	}
+
+	return points
+}
+
+func appendInjectionPoints(points []InjectionPoint, typeInfo reflect.Type, visited map[reflect.Type]bool) []InjectionPoint {
+	for typeInfo.Kind() == reflect.Ptr {
+		typeInfo = typeInfo.Elem()
+	}
+
+	if typeInfo.Kind() != reflect.Struct || visited[typeInfo] {
+		return points
+	}
+	visited[typeInfo] = true
+
+	for i := 0; i < typeInfo.NumField(); i++ {
+		fieldInfo := typeInfo.Field(i)
+		if _, ok := structTagLookup(fieldInfo.Tag, injectTag); !ok {
+			continue
+		}
+
+		options := parseInjectTag(fieldInfo.Tag.Get(injectTag))
+		points = append(points, InjectionPoint{
+			Owner:    typeInfo,
+			Field:    fieldInfo.Name,
+			Type:     fieldInfo.Type,
+			Name:     options.name,
+			Optional: options.optional,
+			Secret:   options.secret,
+		})
+
+		points = appendInjectionPoints(points, fieldInfo.Type, visited)
+	}
+
+	return points
+}
+
+// builderOf returns the builder behind a provider, if it has one.
+func builderOf(provider Provider) *BuilderProvider {
+	switch p := provider.(type) {
+	case *BuilderProvider:
+		return p
+
+	case BuilderProvider:
+		return &p
+
+	case *SingletonProvider:
+		return builderOf(p.Provider)
+
+	case *ConditionalProvider:
+		return builderOf(p.Provider)
+
+	case *resultProvider:
+		return builderOf(p.result.provider)
+	}
+
+	return nil
+}
+
+// providerType returns the type of a provider, or nil if the provider is
+// not fully configured and cannot report one.
+func providerType(provider Provider) (typeInfo reflect.Type) {
+	defer func() {
+		if r := recover(); r != nil {
+			typeInfo = nil
+		}
+	}()
+
+	return provider.GetType()
+}
@@ -0,0 +1,59 @@
+package inject
+
+import "strings"
+
+// LayeredSource combines sources in order of increasing precedence, e.g.
+// defaults, a config file, the environment and then flags. A name is
+// answered by the last layer which has a value for it.
+type LayeredSource struct {
+	Layers []Source
+}
+
+// NewLayeredSource creates a source from layers given in order of increasing
+// precedence.
+func NewLayeredSource(layers ...Source) *LayeredSource {
+	return &LayeredSource{Layers: layers}
+}
+
+// Key describes the layer which answers name or, if none does, every layer
+// in order of precedence.
+func (s LayeredSource) Key(name string) string {
+	if layer, ok := s.Origin(name); ok {
+		return layer.Key(name)
+	}
+
+	var keys []string
+	for i := len(s.Layers) - 1; i >= 0; i-- {
+		keys = append(keys, s.Layers[i].Key(name))
+	}
+
+	return strings.Join(keys, ", ")
+}
+
+func (s LayeredSource) Lookup(name string) (interface{}, bool) {
+	if layer, ok := s.Origin(name); ok {
+		return layer.Lookup(name)
+	}
+
+	return nil, false
+}
+
+// Origin returns the layer which answers name.
+func (s LayeredSource) Origin(name string) (Source, bool) {
+	for i := len(s.Layers) - 1; i >= 0; i-- {
+		if _, ok := s.Layers[i].Lookup(name); ok {
+			return s.Layers[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// OnReload registers listener with every layer whose values can change.
+func (s LayeredSource) OnReload(listener func()) {
+	for _, layer := range s.Layers {
+		if notifier, ok := layer.(ReloadNotifier); ok {
+			notifier.OnReload(listener)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"flag"
+	"fmt"
+	. "github.com/impinj/go-inject/inject"
+	"os"
+	"reflect"
+)
+
+var _ = Describe("LayeredSource", func() {
+	var (
+		fs     *flag.FlagSet
+		source *LayeredSource
+		g      Graph
+	)
+
+	BeforeEach(func() {
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+		fs.String("helloservice.url", "", "")
+
+		source = NewLayeredSource(
+			MapSource{
+				"helloservice.url":  "https://www.example.org/default",
+				"helloservice.port": 80,
+				"debug":             false,
+			},
+			EnvSource{Prefix: "INJECT_LAYER_"},
+			FlagSource{FlagSet: fs},
+		)
+		g = NewGraph(WithSources(source))
+
+		os.Setenv("INJECT_LAYER_HELLOSERVICE_PORT", "8080")
+		os.Setenv("INJECT_LAYER_HELLOSERVICE_URL", "https://www.example.org/env")
+		Expect(fs.Parse([]string{"-helloservice.url=https://www.example.org/flag"})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("INJECT_LAYER_HELLOSERVICE_PORT")
+		os.Unsetenv("INJECT_LAYER_HELLOSERVICE_URL")
+	})
+
+	DescribeTable("Answers with the highest-precedence layer",
+		func(name string, expected interface{}, origin string) {
+			v, err := g.Find(reflect.TypeOf(expected), nil, name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(v).To(Equal(expected))
+			Expect(source.Key(name)).To(Equal(origin))
+		},
+		Entry("Flag over environment and defaults", "helloservice.url", "https://www.example.org/flag", "flag -helloservice.url"),
+		Entry("Environment over defaults", "helloservice.port", 8080, "environment variable INJECT_LAYER_HELLOSERVICE_PORT"),
+		Entry("Defaults", "debug", false, "map entry debug"),
+	)
+
+	It("Lists every layer for missing names", func() {
+		_, err := g.Find(reflect.TypeOf(""), nil, "helloservice.token")
+		Expect(err).To(Equal(fmt.Errorf("Could not find provider for string named helloservice.token (checked %s).",
+			"flag -helloservice.token, environment variable INJECT_LAYER_HELLOSERVICE_TOKEN, map entry helloservice.token")))
+	})
+
+	Describe("Origin", func() {
+		It("Returns the layer answering a name", func() {
+			layer, ok := source.Origin("helloservice.port")
+			Expect(ok).To(BeTrue())
+			Expect(layer).To(Equal(EnvSource{Prefix: "INJECT_LAYER_"}))
+		})
+
+		It("Is reported by the graph", func() {
+			origin, ok := g.Origin(reflect.TypeOf(""), nil, "helloservice.url")
+			Expect(ok).To(BeTrue())
+			Expect(origin).To(Equal("flag -helloservice.url"))
+		})
+
+		It("Reports providers ahead of sources", func() {
+			g.Provide(&ValueProvider{Name: "helloservice.url", Value: "https://www.example.org/provided"})
+			origin, ok := g.Origin(reflect.TypeOf(""), nil, "helloservice.url")
+			Expect(ok).To(BeTrue())
+			Expect(origin).To(Equal("*inject.ValueProvider named helloservice.url"))
+			Expect(g.Find(reflect.TypeOf(""), nil, "helloservice.url")).To(Equal("https://www.example.org/provided"))
+		})
+
+		It("Reports sources when the named provider has another type", func() {
+			g.Provide(&ValueProvider{Name: "helloservice.url", Value: 8080})
+			origin, ok := g.Origin(reflect.TypeOf(""), nil, "helloservice.url")
+			Expect(ok).To(BeTrue())
+			Expect(origin).To(Equal("flag -helloservice.url"))
+		})
+
+		It("Reports ambiguous requests as having no origin", func() {
+			g.Provide(
+				&ValueProvider{Name: "helloservice.url", Value: "https://www.example.org/a"},
+				&ValueProvider{Name: "helloservice.url", Value: "https://www.example.org/b"},
+			)
+			_, ok := g.Origin(reflect.TypeOf(""), nil, "helloservice.url")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("Reports names nothing answers", func() {
+			_, ok := g.Origin(reflect.TypeOf(""), nil, "helloservice.token")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	It("Gives graph sources the same precedence as layers", func() {
+		g = NewGraph(
+			WithSources(MapSource{"helloservice.url": "https://www.example.org/low"}),
+			WithSources(
+				MapSource{"helloservice.url": "https://www.example.org/middle"},
+				MapSource{"helloservice.url": "https://www.example.org/high"},
+			),
+		)
+		Expect(g.Find(reflect.TypeOf(""), nil, "helloservice.url")).To(Equal("https://www.example.org/high"))
+	})
+})
@@ -0,0 +1,106 @@
+package inject
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/trace"
+	"time"
+)
+
+// Listener observes what a graph does. Listeners are called synchronously,
+// in the order they were given, on the goroutine using the graph.
+type Listener interface {
+	OnEvent(event Event)
+}
+
+// ListenerFunc adapts a function to a Listener.
+type ListenerFunc func(event Event)
+
+func (f ListenerFunc) OnEvent(event Event) {
+	f(event)
+}
+
+// WithListeners adds listeners to a graph.
+func WithListeners(listeners ...Listener) Option {
+	return func(g *graph) {
+		g.listeners = append(g.listeners, listeners...)
+	}
+}
+
+func (g *graph) emit(event Event) {
+	for _, listener := range g.listeners {
+		listener.OnEvent(event)
+	}
+}
+
+// traceCandidates reports how a lookup narrows the active providers down.
+func (g *graph) traceCandidates(typeInfo, context reflect.Type, name string) {
+	candidates := selectProvidersByType(g.active(), typeInfo)
+	g.emit(Event{Kind: CandidatesByType, Type: typeInfo, Context: context, Name: name, Candidates: candidates})
+
+	candidates = selectProvidersByContext(candidates, context)
+	g.emit(Event{Kind: CandidatesByContext, Type: typeInfo, Context: context, Name: name, Candidates: candidates})
+
+	candidates = selectProvidersByName(candidates, name)
+	g.emit(Event{Kind: CandidatesByName, Type: typeInfo, Context: context, Name: name, Candidates: candidates})
+}
+
+// resolve resolves a provider, timing it and marking a trace region if it
+// will invoke a builder.
+func (g *graph) resolve(provider Provider) (interface{}, error) {
+	if (len(g.listeners) == 0 && !g.traceRegions) || provider.IsComplete() || !invokesBuilder(provider) {
+		return resolved(provider, provider.Resolve())
+	}
+
+	if g.traceRegions {
+		defer trace.StartRegion(context.Background(), "inject: build "+describeTarget(providerType(provider), provider.GetName())).End()
+	}
+
+	start := time.Now()
+	v := provider.Resolve()
+	g.emit(Event{Kind: BuilderInvoked, Type: providerType(provider), Provider: provider, Duration: time.Since(start)})
+
+	return resolved(provider, v)
+}
+
+// invokesBuilder reports whether resolving a provider may call a builder,
+// either its own or that of the result object it is a field of.
+func invokesBuilder(provider Provider) bool {
+	switch p := provider.(type) {
+	case *outputProvider:
+		return true
+
+	case *ConditionalProvider:
+		return invokesBuilder(p.Provider)
+	}
+
+	return builderOf(provider) != nil
+}
+
+// resolved reports a nil result from a provider as an error, using the
+// cause recorded by the provider if it has one.
+func resolved(provider Provider, v interface{}) (interface{}, error) {
+	if v != nil {
+		return v, nil
+	}
+
+	for {
+		switch p := provider.(type) {
+		case *SingletonProvider:
+			provider = p.Provider
+			continue
+
+		case *ConditionalProvider:
+			provider = p.Provider
+			continue
+
+		case *TypeProvider:
+			if p.err != nil {
+				return nil, fmt.Errorf("Could not resolve %s: %s", providerType(p), p.err)
+			}
+		}
+
+		return nil, fmt.Errorf("Could not resolve %s.", providerType(provider))
+	}
+}
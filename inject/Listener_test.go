@@ -0,0 +1,120 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/impinj/go-inject/inject"
+	"reflect"
+	"time"
+)
+
+var _ = Describe("Listener", func() {
+	var (
+		events []Event
+		g      Graph
+	)
+
+	kinds := func() []EventKind {
+		var retVal []EventKind
+		for _, event := range events {
+			retVal = append(retVal, event.Kind)
+		}
+		return retVal
+	}
+
+	BeforeEach(func() {
+		events = nil
+		g = NewGraph(WithListeners(ListenerFunc(func(event Event) {
+			events = append(events, event)
+		})))
+	})
+
+	It("Reports registered providers", func() {
+		provider := &ValueProvider{Value: CustomA{}}
+		g.Provide(provider)
+
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Kind).To(Equal(ProviderRegistered))
+		Expect(events[0].Provider).To(BeIdenticalTo(provider))
+		Expect(events[0].Type).To(Equal(reflect.TypeOf(CustomA{})))
+	})
+
+	It("Reports how a lookup narrows the candidates", func() {
+		matching := &ValueProvider{Value: CustomA{Val: 1}, Context: reflect.TypeOf(ServicePtrImpl{})}
+		other := &ValueProvider{Value: CustomA{Val: 2}, Context: reflect.TypeOf(StructA{})}
+		g.Provide(
+			&ValueProvider{Value: &ServicePtrImpl{}},
+			matching,
+			other)
+		events = nil
+
+		Expect(g.Resolve()).To(Succeed())
+		Expect(kinds()).To(Equal([]EventKind{
+			LookupStarted,
+			CandidatesByType,
+			CandidatesByContext,
+			CandidatesByName,
+			ProviderChosen,
+			FieldSet,
+			Completed,
+		}))
+
+		Expect(events[1].Candidates).To(ConsistOf(matching, other))
+		Expect(events[2].Candidates).To(ConsistOf(matching))
+		Expect(events[4].Provider).To(BeIdenticalTo(matching))
+		Expect(events[5].Owner).To(Equal(reflect.TypeOf(ServicePtrImpl{})))
+		Expect(events[5].Field).To(Equal("X"))
+	})
+
+	It("Reports failed lookups", func() {
+		g.Provide(&ValueProvider{Value: &ServicePtrImpl{}})
+		events = nil
+
+		Expect(g.Resolve()).NotTo(Succeed())
+		Expect(events[len(events)-2].Kind).To(Equal(ResolutionFailed))
+		Expect(events[len(events)-2].Err).To(HaveOccurred())
+		Expect(events[len(events)-2].Type).To(Equal(reflect.TypeOf((*InterfaceA)(nil)).Elem()))
+
+		Expect(events[len(events)-1].Kind).To(Equal(Completed))
+		Expect(events[len(events)-1].Err).To(HaveOccurred())
+	})
+
+	It("Times builders", func() {
+		g.Provide(&BuilderProvider{Builder: func() CustomA {
+			time.Sleep(time.Millisecond)
+			return CustomA{}
+		}})
+
+		_, err := g.Find(reflect.TypeOf(CustomA{}), nil, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(events[len(events)-1].Kind).To(Equal(BuilderInvoked))
+		Expect(events[len(events)-1].Duration).To(BeNumerically(">=", time.Millisecond))
+	})
+
+	It("Times builders of result objects", func() {
+		type Result struct {
+			Out
+
+			A CustomA
+		}
+
+		g.Provide(&BuilderProvider{Builder: func() Result {
+			time.Sleep(time.Millisecond)
+			return Result{}
+		}})
+
+		_, err := g.Find(reflect.TypeOf(CustomA{}), nil, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(events[len(events)-1].Kind).To(Equal(BuilderInvoked))
+		Expect(events[len(events)-1].Type).To(Equal(reflect.TypeOf(CustomA{})))
+		Expect(events[len(events)-1].Duration).To(BeNumerically(">=", time.Millisecond))
+	})
+
+	It("Names event kinds", func() {
+		Expect(CandidatesByContext.String()).To(Equal("CandidatesByContext"))
+		Expect(EventKind(100).String()).To(Equal("EventKind(100)"))
+	})
+})
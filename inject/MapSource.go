@@ -0,0 +1,25 @@
+package inject
+
+import "strings"
+
+// MapSource supplies named values from a map, e.g. as a layer of defaults.
+// Names are matched case-insensitively.
+type MapSource map[string]interface{}
+
+func (s MapSource) Key(name string) string {
+	return "map entry " + name
+}
+
+func (s MapSource) Lookup(name string) (interface{}, bool) {
+	if v, ok := s[name]; ok {
+		return v, true
+	}
+
+	for k, v := range s {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
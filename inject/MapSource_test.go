@@ -0,0 +1,27 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/impinj/go-inject/inject"
+)
+
+var _ = Describe("MapSource", func() {
+	source := MapSource{"helloservice.url": "https://www.example.org"}
+
+	It("Looks up names case-insensitively", func() {
+		v, ok := source.Lookup("HelloService.Url")
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal("https://www.example.org"))
+	})
+
+	It("Reports missing names", func() {
+		_, ok := source.Lookup("helloservice.port")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Names the entry", func() {
+		Expect(source.Key("helloservice.port")).To(Equal("map entry helloservice.port"))
+	})
+})
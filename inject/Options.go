@@ -0,0 +1,23 @@
+package inject
+
+// Option configures a graph created by NewGraph.
+type Option func(*graph)
+
+// Mode controls how a graph treats types for which no provider was given.
+type Mode int
+
+const (
+	// Strict requires every injected type to have a provider.
+	Strict Mode = iota
+
+	// JustInTime constructs unnamed pointer-to-struct types for which no
+	// provider exists, completes them and shares them as singletons.
+	JustInTime
+)
+
+// WithMode sets the mode of a graph. Graphs are Strict by default.
+func WithMode(mode Mode) Option {
+	return func(g *graph) {
+		g.mode = mode
+	}
+}
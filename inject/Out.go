@@ -0,0 +1,169 @@
+package inject
+
+import "reflect"
+
+// Out marks a struct as a result object. When a builder returns a struct
+// embedding Out, each of its exported fields is also provided separately,
+// named after the field's inject tag if it has one. All of the fields come
+// from a single invocation of the builder.
+type Out struct{}
+
+var outType = reflect.TypeOf(Out{})
+
+func isResultObject(typeInfo reflect.Type) bool {
+	return embeds(typeInfo, outType)
+}
+
+// expandResultObjects appends a provider for every field of every result
+// object produced by the given builders. The builder itself is replaced by a
+// provider of the result object sharing the same invocation. The fields of a
+// conditional builder are active whenever it is.
+func expandResultObjects(providers []Provider) []Provider {
+	var expanded []Provider
+	for _, provider := range providers {
+		inner, conditional := provider, (*ConditionalProvider)(nil)
+		switch p := provider.(type) {
+		case *ConditionalProvider:
+			inner, conditional = p.Provider, p
+
+		case ConditionalProvider:
+			inner, conditional = p.Provider, &p
+		}
+
+		var builder *BuilderProvider
+		switch p := inner.(type) {
+		case *BuilderProvider:
+			builder = p
+
+		case BuilderProvider:
+			builder = &p
+
+		default:
+			expanded = append(expanded, provider)
+			continue
+		}
+
+		typeInfo := reflect.TypeOf(builder.Builder)
+		if typeInfo == nil ||
+			typeInfo.Kind() != reflect.Func ||
+			typeInfo.NumOut() == 0 ||
+			!isResultObject(typeInfo.Out(0)) {
+			expanded = append(expanded, provider)
+			continue
+		}
+
+		result := &resultObject{provider: builder}
+		var parent Provider = &resultProvider{result: result}
+		if conditional != nil {
+			parent = &ConditionalProvider{Provider: parent, When: conditional.When}
+		}
+		expanded = append(expanded, parent)
+
+		resultType := typeInfo.Out(0)
+		for i := 0; i < resultType.NumField(); i++ {
+			fieldInfo := resultType.Field(i)
+			if fieldInfo.PkgPath != "" || fieldInfo.Type == outType {
+				continue
+			}
+
+			var output Provider = &outputProvider{
+				result: result,
+				field:  i,
+				name:   parseInjectTag(fieldInfo.Tag.Get(injectTag)).name,
+			}
+			if conditional != nil {
+				output = &ConditionalProvider{Provider: output, When: []Condition{activeWith(parent)}}
+			}
+			expanded = append(expanded, output)
+		}
+	}
+
+	return expanded
+}
+
+// activeWith holds when provider is active.
+func activeWith(provider Provider) Condition {
+	return func(state ConditionState) bool {
+		return indexOfProvider(state.Active, provider) >= 0
+	}
+}
+
+// resultObject invokes a builder at most once on behalf of the providers of
+// its fields.
+type resultObject struct {
+	provider Provider
+	value    reflect.Value
+}
+
+func (r *resultObject) resolve() (reflect.Value, bool) {
+	if !r.value.IsValid() {
+		if v := r.provider.Resolve(); v != nil {
+			r.value = reflect.ValueOf(v)
+		}
+	}
+
+	return r.value, r.value.IsValid()
+}
+
+// resultProvider provides the result object itself.
+type resultProvider struct {
+	result *resultObject
+}
+
+func (p resultProvider) GetContext() reflect.Type {
+	return p.result.provider.GetContext()
+}
+
+func (p resultProvider) GetName() string {
+	return p.result.provider.GetName()
+}
+
+func (p resultProvider) GetType() reflect.Type {
+	return p.result.provider.GetType()
+}
+
+func (p resultProvider) GetAs() []reflect.Type {
+	return p.result.provider.(AsProvider).GetAs()
+}
+
+func (p resultProvider) IsComplete() bool {
+	return p.result.value.IsValid()
+}
+
+func (p resultProvider) Resolve() interface{} {
+	if v, ok := p.result.resolve(); ok {
+		return v.Interface()
+	}
+
+	return nil
+}
+
+type outputProvider struct {
+	result *resultObject
+	field  int
+	name   string
+}
+
+func (p outputProvider) GetContext() reflect.Type {
+	return p.result.provider.GetContext()
+}
+
+func (p outputProvider) GetName() string {
+	return p.name
+}
+
+func (p outputProvider) GetType() reflect.Type {
+	return p.result.provider.GetType().Field(p.field).Type
+}
+
+func (p outputProvider) IsComplete() bool {
+	return p.result.value.IsValid()
+}
+
+func (p outputProvider) Resolve() interface{} {
+	if v, ok := p.result.resolve(); ok {
+		return v.Field(p.field).Interface()
+	}
+
+	return nil
+}
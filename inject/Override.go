@@ -0,0 +1,130 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Override replaces the providers which each of the given providers stands
+// in for: those with the same name and context, bound as the same set of
+// types. An override which should replace an interface binding must declare
+// that interface in its As. An override takes the position of the first
+// provider it replaces and the others are removed, so that it is only bound
+// once. It is an error for an override not to replace anything, or for two
+// overrides to replace the same provider, in which case the graph is left
+// unchanged.
+func (g *graph) Override(providers ...Provider) error {
+	overrides := expandResultObjects(providers)
+	replacements := make([][]int, len(overrides))
+	replacedBy := map[int]int{}
+
+	var missing, conflicts []string
+	for i, override := range overrides {
+		for j, provider := range g.providers {
+			if !canOverride(override, provider) {
+				continue
+			}
+
+			if k, ok := replacedBy[j]; ok {
+				conflicts = append(conflicts, fmt.Sprintf("%s (overrides %d and %d)", describeBinding(provider), k, i))
+				continue
+			}
+
+			replacedBy[j] = i
+			replacements[i] = append(replacements[i], j)
+		}
+
+		if len(replacements[i]) == 0 {
+			missing = append(missing, describeBinding(override))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("Found multiple overrides for %s.", strings.Join(conflicts, ", "))
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("Could not find bindings to override for %s.", strings.Join(missing, ", "))
+	}
+
+	removed := map[int]bool{}
+	for i, override := range overrides {
+		g.providers[replacements[i][0]] = override
+		for _, j := range replacements[i][1:] {
+			removed[j] = true
+		}
+	}
+
+	var kept []Provider
+	for j, provider := range g.providers {
+		if !removed[j] {
+			kept = append(kept, provider)
+		}
+	}
+	g.providers = kept
+	g.activeProviders = nil
+
+	for _, override := range overrides {
+		g.emit(Event{Kind: ProviderRegistered, Type: providerType(override), Provider: override})
+	}
+
+	return nil
+}
+
+// canOverride reports whether override stands in for provider: both have
+// the same name and context and are bound as the same set of types.
+func canOverride(override, provider Provider) bool {
+	if override.GetContext() != provider.GetContext() ||
+		!strings.EqualFold(override.GetName(), provider.GetName()) {
+		return false
+	}
+
+	return sameTypes(boundTypes(override), boundTypes(provider))
+}
+
+// sameTypes reports whether two lists hold the same set of types.
+func sameTypes(a, b []reflect.Type) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+
+	for _, types := range [][2][]reflect.Type{{a, b}, {b, a}} {
+		for _, typeInfo := range types[0] {
+			found := false
+			for _, other := range types[1] {
+				if typeInfo == other {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// boundTypes returns the types a provider was declared as or, if it did not
+// declare any, its own type.
+func boundTypes(provider Provider) []reflect.Type {
+	if asProvider, ok := provider.(AsProvider); ok && len(asProvider.GetAs()) > 0 {
+		return asProvider.GetAs()
+	}
+
+	if typeInfo := providerType(provider); typeInfo != nil {
+		return []reflect.Type{typeInfo}
+	}
+
+	return nil
+}
+
+func describeBinding(provider Provider) string {
+	return fmt.Sprintf("type: %s, context: %s, name: %s",
+		boundTypes(provider),
+		provider.GetContext(),
+		provider.GetName())
+}
@@ -0,0 +1,128 @@
+package inject
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// WithTraceRegions marks every builder invocation as a runtime/trace region
+// named after the type and name being built, so that slow builders stand
+// out in `go tool trace`.
+func WithTraceRegions() Option {
+	return func(g *graph) {
+		g.traceRegions = true
+	}
+}
+
+// ProfileEntry totals the time spent building one provider, or completing
+// one type.
+type ProfileEntry struct {
+	Operation string
+	Type      reflect.Type
+	Name      string
+	Count     int
+	Total     time.Duration
+	Max       time.Duration
+}
+
+// Profile is a Listener which records how long builders and completions
+// take. Completion times include any nested builds and completions.
+type Profile struct {
+	mutex   sync.Mutex
+	entries map[profileKey]*ProfileEntry
+}
+
+type profileKey struct {
+	operation string
+	typeInfo  reflect.Type
+	name      string
+}
+
+// NewProfile creates an empty profile, to be passed to WithListeners.
+func NewProfile() *Profile {
+	return &Profile{entries: map[profileKey]*ProfileEntry{}}
+}
+
+func (p *Profile) OnEvent(event Event) {
+	var key profileKey
+	switch event.Kind {
+	case BuilderInvoked:
+		key = profileKey{"build", event.Type, event.Provider.GetName()}
+
+	case Completed:
+		key = profileKey{"complete", event.Type, ""}
+
+	default:
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	entry, ok := p.entries[key]
+	if !ok {
+		entry = &ProfileEntry{Operation: key.operation, Type: key.typeInfo, Name: key.name}
+		p.entries[key] = entry
+	}
+
+	entry.Count++
+	entry.Total += event.Duration
+	if event.Duration > entry.Max {
+		entry.Max = event.Duration
+	}
+}
+
+// Report returns the recorded entries, slowest in total first.
+func (p *Profile) Report() []ProfileEntry {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	report := make([]ProfileEntry, 0, len(p.entries))
+	for _, entry := range p.entries {
+		report = append(report, *entry)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Total != report[j].Total {
+			return report[i].Total > report[j].Total
+		}
+
+		if report[i].Operation != report[j].Operation {
+			return report[i].Operation < report[j].Operation
+		}
+
+		return describeTarget(report[i].Type, report[i].Name) < describeTarget(report[j].Type, report[j].Name)
+	})
+
+	return report
+}
+
+// WriteReport writes the report as a table.
+func (p *Profile) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "OPERATION\tTARGET\tCOUNT\tTOTAL\tMAX")
+	for _, entry := range p.Report() {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n",
+			entry.Operation,
+			describeTarget(entry.Type, entry.Name),
+			entry.Count,
+			entry.Total,
+			entry.Max)
+	}
+
+	return tw.Flush()
+}
+
+func describeTarget(typeInfo reflect.Type, name string) string {
+	description := fmt.Sprint(typeInfo)
+	if name != "" {
+		description += " named " + name
+	}
+
+	return description
+}
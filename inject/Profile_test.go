@@ -0,0 +1,100 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	. "github.com/impinj/go-inject/inject"
+	"reflect"
+	"runtime/trace"
+	"strings"
+	"time"
+)
+
+type ProfiledSlow struct {
+	Val int
+}
+
+type ProfiledFast struct {
+	Val int
+}
+
+type ProfiledRoot struct {
+	Slow ProfiledSlow `inject:""`
+	Fast ProfiledFast `inject:"fast"`
+}
+
+var _ = Describe("Profile", func() {
+	var (
+		g       Graph
+		profile *Profile
+		root    *ProfiledRoot
+	)
+
+	BeforeEach(func() {
+		profile = NewProfile()
+		root = &ProfiledRoot{}
+		g = NewGraph(WithListeners(profile), WithTraceRegions())
+		g.Provide(
+			&ValueProvider{Value: root},
+			&BuilderProvider{Builder: func() ProfiledSlow {
+				time.Sleep(5 * time.Millisecond)
+				return ProfiledSlow{}
+			}},
+			&BuilderProvider{Name: "fast", Builder: func() ProfiledFast {
+				return ProfiledFast{}
+			}})
+	})
+
+	It("Reports builders and completions, slowest first", func() {
+		Expect(g.Resolve()).To(Succeed())
+
+		report := profile.Report()
+		Expect(report).To(HaveLen(3))
+
+		Expect(report[0].Operation).To(Equal("complete"))
+		Expect(report[0].Type).To(Equal(reflect.TypeOf(root)))
+		Expect(report[0].Total).To(BeNumerically(">=", 5*time.Millisecond))
+
+		Expect(report[1].Operation).To(Equal("build"))
+		Expect(report[1].Type).To(Equal(reflect.TypeOf(ProfiledSlow{})))
+		Expect(report[1].Count).To(Equal(1))
+		Expect(report[1].Max).To(Equal(report[1].Total))
+
+		Expect(report[2].Type).To(Equal(reflect.TypeOf(ProfiledFast{})))
+		Expect(report[2].Name).To(Equal("fast"))
+	})
+
+	It("Accumulates repeated builds", func() {
+		for i := 0; i < 3; i++ {
+			_, err := g.Find(reflect.TypeOf(ProfiledFast{}), nil, "fast")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(profile.Report()).To(HaveLen(1))
+		Expect(profile.Report()[0].Count).To(Equal(3))
+	})
+
+	It("Writes the report as a table", func() {
+		Expect(g.Resolve()).To(Succeed())
+
+		var buf bytes.Buffer
+		Expect(profile.WriteReport(&buf)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(4))
+		Expect(lines[0]).To(MatchRegexp(`^OPERATION\s+TARGET\s+COUNT\s+TOTAL\s+MAX$`))
+		Expect(lines[2]).To(MatchRegexp(`^build\s+inject_test.ProfiledSlow\s+1\s+`))
+		Expect(lines[3]).To(ContainSubstring("inject_test.ProfiledFast named fast"))
+	})
+
+	It("Marks builders as trace regions", func() {
+		var buf bytes.Buffer
+		Expect(trace.Start(&buf)).To(Succeed())
+		Expect(g.Resolve()).To(Succeed())
+		trace.Stop()
+
+		Expect(buf.String()).To(ContainSubstring("inject: build inject_test.ProfiledSlow"))
+	})
+})
@@ -10,3 +10,12 @@ type Provider interface {
 
 	Resolve() interface{}
 }
+
+// AsProvider is implemented by providers which declare the exact set of
+// types they satisfy. A provider with a non-empty type set is only selected
+// for those types, instead of every type its value is assignable to.
+type AsProvider interface {
+	Provider
+
+	GetAs() []reflect.Type
+}
@@ -0,0 +1,166 @@
+package inject
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// ReloadNotifier is implemented by sources whose values can change while
+// the process runs.
+type ReloadNotifier interface {
+	// OnReload registers a listener which is called after the source's
+	// values are reloaded.
+	OnReload(listener func())
+}
+
+// ReloadableSource supplies named values from a source which is loaded
+// again by Reload, or whenever the file at Path changes while it is being
+// watched. Values are looked up in the most recently loaded source. A
+// ReloadableSource created from its fields has no values until its first
+// Reload.
+type ReloadableSource struct {
+	Path    string
+	Load    func() (Source, error)
+	OnError func(error)
+
+	mutex     sync.RWMutex
+	current   Source
+	listeners []func()
+}
+
+// NewReloadableSource creates a source which is loaded, and reloaded, by
+// load.
+func NewReloadableSource(load func() (Source, error)) (*ReloadableSource, error) {
+	s := &ReloadableSource{Load: load}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// NewReloadableFile creates a source from a JSON or YAML file which can be
+// watched for changes.
+func NewReloadableFile(path string) (*ReloadableSource, error) {
+	s, err := NewReloadableSource(func() (Source, error) {
+		return LoadConfigFile(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Path = path
+	return s, nil
+}
+
+func (s *ReloadableSource) Key(name string) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.current == nil {
+		return "unloaded source key " + name
+	}
+
+	return s.current.Key(name)
+}
+
+func (s *ReloadableSource) Lookup(name string) (interface{}, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.current == nil {
+		return nil, false
+	}
+
+	return s.current.Lookup(name)
+}
+
+func (s *ReloadableSource) OnReload(listener func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+// Reload loads the source again and notifies listeners. If loading fails the
+// previous values are kept.
+func (s *ReloadableSource) Reload() error {
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	s.mutex.RLock()
+	listeners := append([]func(){}, s.listeners...)
+	s.mutex.RUnlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+
+	return nil
+}
+
+// Watch polls the file at Path every interval and reloads the source when
+// the file's size or modification time changes. Errors are passed to
+// OnError, if set. Calling the returned function stops watching.
+func (s *ReloadableSource) Watch(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	last, _ := os.Stat(s.Path)
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+
+			case <-ticker.C:
+				info, err := os.Stat(s.Path)
+				if err != nil {
+					s.reportError(err)
+					continue
+				}
+
+				if last != nil &&
+					info.ModTime().Equal(last.ModTime()) &&
+					info.Size() == last.Size() {
+					continue
+				}
+
+				last = info
+				if err := s.Reload(); err != nil {
+					s.reportError(err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
+
+func (s *ReloadableSource) load() error {
+	current, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.current = current
+	return nil
+}
+
+func (s *ReloadableSource) reportError(err error) {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+}
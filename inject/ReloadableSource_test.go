@@ -0,0 +1,141 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"errors"
+	. "github.com/impinj/go-inject/inject"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("ReloadableSource", func() {
+	lookup := func(source Source, name string) interface{} {
+		v, _ := source.Lookup(name)
+		return v
+	}
+
+	Context("Reloaded explicitly", func() {
+		var (
+			values  MapSource
+			loadErr error
+			source  *ReloadableSource
+		)
+
+		BeforeEach(func() {
+			values = MapSource{"ratelimit": 10}
+			loadErr = nil
+
+			var err error
+			source, err = NewReloadableSource(func() (Source, error) {
+				return values, loadErr
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Looks up the loaded values", func() {
+			Expect(lookup(source, "ratelimit")).To(Equal(10))
+			Expect(source.Key("ratelimit")).To(Equal("map entry ratelimit"))
+		})
+
+		It("Looks up the reloaded values and notifies listeners", func() {
+			reloads := 0
+			source.OnReload(func() {
+				reloads++
+			})
+
+			values = MapSource{"ratelimit": 20}
+			Expect(source.Reload()).To(Succeed())
+			Expect(lookup(source, "ratelimit")).To(Equal(20))
+			Expect(reloads).To(Equal(1))
+		})
+
+		It("Keeps the previous values when loading fails", func() {
+			values = MapSource{}
+			loadErr = errors.New("Encountered error")
+
+			Expect(source.Reload()).ToNot(Succeed())
+			Expect(lookup(source, "ratelimit")).To(Equal(10))
+		})
+
+		It("Fails to create when the first load fails", func() {
+			_, err := NewReloadableSource(func() (Source, error) {
+				return nil, errors.New("Encountered error")
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Created from its fields", func() {
+		It("Has no values until it is reloaded", func() {
+			source := &ReloadableSource{Load: func() (Source, error) {
+				return MapSource{"ratelimit": 10}, nil
+			}}
+
+			_, ok := source.Lookup("ratelimit")
+			Expect(ok).To(BeFalse())
+			Expect(source.Key("ratelimit")).To(ContainSubstring("ratelimit"))
+
+			Expect(source.Reload()).To(Succeed())
+			Expect(lookup(source, "ratelimit")).To(Equal(10))
+		})
+
+		It("Is an empty source when it is the zero value", func() {
+			var source ReloadableSource
+
+			_, ok := source.Lookup("ratelimit")
+			Expect(ok).To(BeFalse())
+			Expect(source.Key("ratelimit")).To(ContainSubstring("ratelimit"))
+		})
+	})
+
+	Context("Watching a file", func() {
+		var (
+			dir, path string
+			source    *ReloadableSource
+			errs      chan error
+			stop      func()
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "inject")
+			Expect(err).ToNot(HaveOccurred())
+
+			path = filepath.Join(dir, "config.json")
+			Expect(ioutil.WriteFile(path, []byte(`{"ratelimit": 10}`), 0600)).To(Succeed())
+
+			source, err = NewReloadableFile(path)
+			Expect(err).ToNot(HaveOccurred())
+
+			errs = make(chan error, 10)
+			source.OnError = func(err error) {
+				errs <- err
+			}
+			stop = source.Watch(10 * time.Millisecond)
+		})
+
+		AfterEach(func() {
+			stop()
+			os.RemoveAll(dir)
+		})
+
+		It("Reloads when the file changes", func() {
+			Expect(ioutil.WriteFile(path, []byte(`{"ratelimit": 200}`), 0600)).To(Succeed())
+
+			Eventually(func() interface{} {
+				return lookup(source, "ratelimit")
+			}).Should(BeEquivalentTo(200))
+		})
+
+		It("Reports errors and keeps the previous values", func() {
+			Expect(ioutil.WriteFile(path, []byte(`{"ratelimit": `), 0600)).To(Succeed())
+
+			Eventually(errs).Should(Receive())
+			Expect(lookup(source, "ratelimit")).To(BeEquivalentTo(10))
+		})
+	})
+})
@@ -0,0 +1,49 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const redacted string = "[REDACTED]"
+
+var secretType = reflect.TypeOf(Secret{})
+
+// Secret holds a sensitive value, such as a password. It is redacted when
+// formatted or marshalled, so it never appears in error messages or graph
+// descriptions. Sources convert strings into secrets for fields of this
+// type. Fields of other types can be tagged `inject:"name,secret"` to keep
+// their values out of conversion errors.
+type Secret struct {
+	value string
+}
+
+// NewSecret wraps value in a Secret.
+func NewSecret(value string) Secret {
+	return Secret{value: value}
+}
+
+// Value returns the secret value.
+func (s Secret) Value() string {
+	return s.value
+}
+
+func (s Secret) Format(f fmt.State, verb rune) {
+	f.Write([]byte(redacted))
+}
+
+func (s Secret) GoString() string {
+	return redacted
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+func (s Secret) String() string {
+	return redacted
+}
@@ -0,0 +1,36 @@
+package inject
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// SecretDirSource supplies named secrets from the files in a directory, such
+// as the secrets mounted into a container. The file named after the
+// requested name is read and trailing newlines are removed. Values are
+// returned as Secrets, and are unwrapped when injected into other types.
+type SecretDirSource struct {
+	Dir string
+}
+
+func (s SecretDirSource) Key(name string) string {
+	return "secret file " + s.path(name)
+}
+
+func (s SecretDirSource) Lookup(name string) (interface{}, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, false
+	}
+
+	b, err := ioutil.ReadFile(s.path(name))
+	if err != nil {
+		return nil, false
+	}
+
+	return NewSecret(strings.TrimRight(string(b), "\r\n")), true
+}
+
+func (s SecretDirSource) path(name string) string {
+	return filepath.Join(s.Dir, name)
+}
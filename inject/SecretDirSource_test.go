@@ -0,0 +1,63 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/impinj/go-inject/inject"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("SecretDirSource", func() {
+	var (
+		dir    string
+		source SecretDirSource
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "inject")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "db.password"), []byte("hunter2\n"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "db.port"), []byte("5432"), 0600)).To(Succeed())
+
+		source = SecretDirSource{Dir: dir}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Reads secrets from files named after the name", func() {
+		v, ok := source.Lookup("db.password")
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal(NewSecret("hunter2")))
+	})
+
+	It("Reports missing files", func() {
+		_, ok := source.Lookup("db.user")
+		Expect(ok).To(BeFalse())
+		Expect(source.Key("db.user")).To(Equal("secret file " + filepath.Join(dir, "db.user")))
+	})
+
+	It("Does not read outside the directory", func() {
+		_, ok := source.Lookup("../db.password")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Injects secrets and unwraps them for other types", func() {
+		type Service struct {
+			Password Secret `inject:"db.password"`
+			Port     int    `inject:"db.port"`
+		}
+
+		g := NewGraph(WithSources(source))
+
+		var s Service
+		Expect(g.Complete(&s)).To(Succeed())
+		Expect(s.Password.Value()).To(Equal("hunter2"))
+		Expect(s.Port).To(Equal(5432))
+	})
+})
@@ -0,0 +1,126 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"fmt"
+	. "github.com/impinj/go-inject/inject"
+	"reflect"
+)
+
+var _ = Describe("Secret", func() {
+	secret := NewSecret("hunter2")
+
+	It("Holds the value", func() {
+		Expect(secret.Value()).To(Equal("hunter2"))
+	})
+
+	DescribeTable("Is redacted when formatted",
+		func(format string) {
+			Expect(fmt.Sprintf(format, secret)).To(Equal("[REDACTED]"))
+		},
+		Entry("%s", "%s"),
+		Entry("%v", "%v"),
+		Entry("%+v", "%+v"),
+		Entry("%#v", "%#v"),
+		Entry("%q", "%q"),
+		Entry("%x", "%x"),
+	)
+
+	It("Is redacted when nested in other values", func() {
+		type Config struct {
+			Password Secret
+		}
+
+		Expect(fmt.Sprintf("%+v", Config{Password: secret})).ToNot(ContainSubstring("hunter2"))
+	})
+
+	It("Is redacted when marshalled", func() {
+		b, err := json.Marshal(map[string]Secret{"password": secret})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(Equal(`{"password":"[REDACTED]"}`))
+	})
+
+	Describe("Injected from a source", func() {
+		It("Wraps strings", func() {
+			g := NewGraph(WithSources(MapSource{"db.password": "correct horse"}))
+
+			v, err := g.Find(reflect.TypeOf(secret), nil, "db.password")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(v.(Secret).Value()).To(Equal("correct horse"))
+		})
+
+		It("Does not leak the value in conversion errors", func() {
+			g := NewGraph(WithSources(MapSource{"db.port": NewSecret("hunter2")}))
+
+			_, err := g.Find(reflect.TypeOf(0), nil, "db.port")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		})
+
+		It("Validates the value without leaking it", func() {
+			type Config struct {
+				Password Secret `validate:"min=8"`
+			}
+
+			err := Validate("db", Config{Password: secret})
+			Expect(err).To(MatchError("Invalid configuration: db.password must be at least 8."))
+		})
+	})
+
+	Describe("Injected into fields tagged secret", func() {
+		type Database struct {
+			Password string `inject:"db.password,secret"`
+			Port     int    `inject:"db.port,secret"`
+		}
+
+		It("Injects the value", func() {
+			g := NewGraph(WithSources(MapSource{"db.password": NewSecret("hunter2"), "db.port": "5432"}))
+
+			db := &Database{}
+			Expect(g.Complete(db)).To(Succeed())
+			Expect(db.Password).To(Equal("hunter2"))
+			Expect(db.Port).To(Equal(5432))
+		})
+
+		It("Does not leak the value in conversion errors", func() {
+			g := NewGraph(WithSources(MapSource{"db.password": "", "db.port": "hunter2"}))
+
+			err := g.Complete(&Database{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		})
+
+		It("Does not leak the value in conversion errors for other requests with the name", func() {
+			g := NewGraph(WithSources(MapSource{"db.password": "", "db.port": "hunter2"}))
+			g.Provide(&ValueProvider{Value: &Database{}})
+
+			_, err := g.Find(reflect.TypeOf(0), nil, "db.port")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		})
+
+		It("Does not leak the value in validation errors", func() {
+			g := NewGraph(WithSources(MapSource{"db.password": "", "db.port": "hunter2"}))
+			g.Provide(&ValueProvider{Value: &Database{}})
+
+			err := g.Resolve()
+			Expect(err).To(BeAssignableToTypeOf(&ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("db.port could not be converted"))
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		})
+
+		It("Is marked in descriptions", func() {
+			g := NewGraph(WithSources(MapSource{"db.password": "", "db.port": "5432"}))
+			g.Provide(&ValueProvider{Value: &Database{}})
+
+			deps := g.Describe().Providers[0].Dependencies
+			Expect(deps).To(HaveLen(2))
+			Expect(deps[0].Secret).To(BeTrue())
+			Expect(deps[1].Secret).To(BeTrue())
+		})
+	})
+})
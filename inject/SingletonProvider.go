@@ -1,10 +1,20 @@
 package inject
 
+import "reflect"
+
 type SingletonProvider struct {
 	Provider
 	Value interface{}
 }
 
+func (p SingletonProvider) GetAs() []reflect.Type {
+	if asProvider, ok := p.Provider.(AsProvider); ok {
+		return asProvider.GetAs()
+	}
+
+	return nil
+}
+
 func (p SingletonProvider) IsComplete() bool {
 	return p.Value != nil
 }
@@ -0,0 +1,196 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Source supplies named values, such as configuration, for named injections
+// that no provider satisfies. Values are returned in their raw form, either
+// a string or a decoded tree of maps, slices and primitives, and are
+// converted to the requested type by the graph.
+type Source interface {
+	// Lookup returns the raw value stored under name and whether it exists.
+	Lookup(name string) (interface{}, bool)
+
+	// Key describes where the source looks for name, e.g. the environment
+	// variable it reads, for use in error messages.
+	Key(name string) string
+}
+
+// WithSources adds sources to a graph in order of increasing precedence, as
+// with NewLayeredSource: a name is answered by the last source which has a
+// value for it. Sources added later take precedence over earlier ones.
+func WithSources(sources ...Source) Option {
+	return func(g *graph) {
+		// g.sources is kept in order of decreasing precedence.
+		for _, source := range sources {
+			g.sources = append([]Source{source}, g.sources...)
+		}
+	}
+}
+
+// findInSources converts the first value found for name in the graph's
+// sources into a provider for typeInfo. Conversion errors do not include
+// the value when the request, or any injection point for name, is secret.
+func (g *graph) findInSources(typeInfo reflect.Type, name string, secret bool) (Provider, error) {
+	if isDynamic(typeInfo) && g.inSources(name) {
+		handle, err := g.dynamic(typeInfo, name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ValueProvider{
+			Name:     name,
+			Complete: true,
+			Value:    handle,
+		}, nil
+	}
+
+	for _, source := range g.sources {
+		raw, ok := source.Lookup(name)
+		if !ok {
+			continue
+		}
+
+		value, err := convertValue(raw, typeInfo, secret || g.isSecret(name))
+		if err != nil {
+			return nil, fmt.Errorf("Could not convert %s to %s: %s.", source.Key(name), typeInfo, err)
+		}
+
+		if err := Validate(name, value); err != nil {
+			return nil, err
+		}
+
+		return &ValueProvider{
+			Name:     name,
+			Complete: true,
+			Value:    value,
+		}, nil
+	}
+
+	var keys []string
+	for _, source := range g.sources {
+		keys = append(keys, source.Key(name))
+	}
+
+	return nil, fmt.Errorf("Could not find provider for %s named %s (checked %s).",
+		typeInfo,
+		name,
+		strings.Join(keys, ", "))
+}
+
+// inSources reports whether any of the graph's sources has a value for name.
+func (g *graph) inSources(name string) bool {
+	for _, source := range g.sources {
+		if _, ok := source.Lookup(name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSecret reports whether any of the graph's injection points declares
+// name secret.
+func (g *graph) isSecret(name string) bool {
+	for _, point := range g.InjectionPoints() {
+		if point.Secret && point.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Origin describes where the value for a request comes from: the provider
+// which Find would choose or, failing any provider, the source which answers
+// the name. Ambiguous requests have no origin.
+func (g *graph) Origin(typeInfo, context reflect.Type, name string) (string, bool) {
+	switch providers := selectProviders(g.active(), typeInfo, context, name); len(providers) {
+	case 0:
+
+	case 1:
+		if name == "" {
+			return fmt.Sprintf("%T", providers[0]), true
+		}
+
+		return fmt.Sprintf("%T named %s", providers[0], providers[0].GetName()), true
+
+	default:
+		return "", false
+	}
+
+	if name != "" {
+		for _, source := range g.sources {
+			if _, ok := source.Lookup(name); ok {
+				return source.Key(name), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// validateSources converts and validates every named value which the
+// graph's sources supply to its injection points, and reports required names
+// which nothing supplies, so that every invalid value is reported at once.
+func (g *graph) validateSources() error {
+	if len(g.sources) == 0 {
+		return nil
+	}
+
+	var violations []Violation
+	checked := map[string]bool{}
+
+	for _, point := range g.InjectionPoints() {
+		id := point.Name + " " + point.Type.String()
+		if point.Name == "" ||
+			isDynamic(point.Type) ||
+			checked[id] ||
+			len(selectProviders(g.active(), point.Type, point.Owner, point.Name)) > 0 {
+			continue
+		}
+		checked[id] = true
+
+		found := false
+		for _, source := range g.sources {
+			raw, ok := source.Lookup(point.Name)
+			if !ok {
+				continue
+			}
+			found = true
+
+			value, err := convertValue(raw, point.Type, point.Secret)
+			if err != nil {
+				violations = append(violations, Violation{
+					Key:     point.Name,
+					Message: fmt.Sprintf("could not be converted from %s to %s: %s", source.Key(point.Name), point.Type, err),
+				})
+			} else if err := Validate(point.Name, value); err != nil {
+				violations = append(violations, err.(*ValidationError).Violations...)
+			}
+
+			break
+		}
+
+		if !found && !point.Optional {
+			var keys []string
+			for _, source := range g.sources {
+				keys = append(keys, source.Key(point.Name))
+			}
+
+			violations = append(violations, Violation{
+				Key:     point.Name,
+				Message: fmt.Sprintf("is missing (checked %s)", strings.Join(keys, ", ")),
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
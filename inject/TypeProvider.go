@@ -0,0 +1,116 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Scope controls how many instances a TypeProvider constructs.
+type Scope int
+
+const (
+	// Transient constructs a new instance every time one is resolved.
+	Transient Scope = iota
+
+	// Singleton constructs a single instance and shares it.
+	Singleton
+)
+
+// TypeProvider constructs instances of Type with reflect.New and completes
+// their inject-tagged fields through ResolveContext. Type may be a struct or
+// a pointer to a struct.
+type TypeProvider struct {
+	Name           string
+	Context        reflect.Type
+	Type           reflect.Type
+	Scope          Scope
+	ResolveContext Graph
+	As             []reflect.Type
+	Value          interface{}
+
+	// err is the reason the last Resolve returned nil.
+	err error
+
+	// resolving is set while an instance is being completed, so that a
+	// cycle back to this provider is reported rather than recursing.
+	resolving bool
+}
+
+// Bind returns a provider which constructs impl whenever iface is requested.
+func Bind(resolveContext Graph, iface, impl reflect.Type) *TypeProvider {
+	return &TypeProvider{
+		Type:           impl,
+		ResolveContext: resolveContext,
+		As:             []reflect.Type{iface},
+	}
+}
+
+func (p TypeProvider) GetAs() []reflect.Type {
+	return p.As
+}
+
+func (p TypeProvider) GetContext() reflect.Type {
+	return p.Context
+}
+
+func (p TypeProvider) GetName() string {
+	return p.Name
+}
+
+func (p TypeProvider) GetType() reflect.Type {
+	return p.Type
+}
+
+func (p TypeProvider) IsComplete() bool {
+	return p.Value != nil
+}
+
+func (p *TypeProvider) Resolve() interface{} {
+	if p.Value != nil {
+		return p.Value
+	}
+
+	if p.Type == nil {
+		return nil
+	}
+
+	if p.resolving {
+		p.err = fmt.Errorf("Found a dependency cycle through %s.", p.Type)
+		return nil
+	}
+	p.resolving = true
+	defer func() {
+		p.resolving = false
+	}()
+
+	var v reflect.Value
+	switch p.Type.Kind() {
+	case reflect.Ptr:
+		v = reflect.New(p.Type.Elem())
+
+	default:
+		v = reflect.New(p.Type)
+	}
+
+	// Pointer singletons are shared before they are completed so that cycles
+	// through them resolve to the same instance.
+	if p.Scope == Singleton && p.Type.Kind() == reflect.Ptr {
+		p.Value = v.Interface()
+	}
+
+	if err := p.ResolveContext.Complete(v.Interface()); err != nil {
+		p.Value, p.err = nil, err
+		return nil
+	}
+	p.err = nil
+
+	if p.Type.Kind() != reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if p.Scope == Singleton {
+		p.Value = v.Interface()
+	}
+
+	return v.Interface()
+}
@@ -0,0 +1,114 @@
+package inject_test
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"errors"
+	. "github.com/impinj/go-inject/inject"
+	"github.com/impinj/go-inject/inject/mock"
+	. "reflect"
+)
+
+var _ = Describe("TypeProvider", func() {
+	var (
+		mockCtrl  *gomock.Controller
+		mockGraph *mock_inject.MockGraph
+		p         *TypeProvider
+	)
+
+	type Struct struct {
+		State int
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockGraph = mock_inject.NewMockGraph(mockCtrl)
+		p = &TypeProvider{ResolveContext: mockGraph}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Bind", func() {
+		It("Provides the implementation as the interface", func() {
+			iface := TypeOf((*InterfaceA)(nil)).Elem()
+			impl := TypeOf((*PtrDecorated)(nil))
+
+			p = Bind(mockGraph, iface, impl)
+			Expect(p.GetType()).To(Equal(impl))
+			Expect(p.GetAs()).To(Equal([]Type{iface}))
+			Expect(p.ResolveContext).To(Equal(mockGraph))
+		})
+	})
+
+	DescribeTable("IsComplete",
+		func(v interface{}, expected bool) {
+			p.Value = v
+			Expect(p.IsComplete()).To(Equal(expected))
+		},
+		Entry("Nil value", nil, false),
+		Entry("Non-nil value", &Struct{}, true),
+	)
+
+	Describe("Resolve", func() {
+		Context("With no type", func() {
+			It("Returns nil", func() {
+				Expect(p.Resolve()).To(BeNil())
+			})
+		})
+
+		DescribeTable("Constructs and completes the type",
+			func(typeInfo Type) {
+				p.Type = typeInfo
+				mockGraph.EXPECT().Complete(gomock.Any()).Do(func(v interface{}) {
+					Expect(TypeOf(v)).To(Equal(PtrTo(TypeOf(Struct{}))))
+				}).Return(nil)
+
+				Expect(TypeOf(p.Resolve())).To(Equal(typeInfo))
+			},
+			Entry("Value", TypeOf(Struct{})),
+			Entry("Pointer", TypeOf(&Struct{})),
+		)
+
+		Context("When completion fails", func() {
+			BeforeEach(func() {
+				p.Type = TypeOf(&Struct{})
+				p.Scope = Singleton
+				mockGraph.EXPECT().Complete(gomock.Any()).Return(errors.New("Encountered error"))
+			})
+
+			It("Returns nil and does not keep the instance", func() {
+				Expect(p.Resolve()).To(BeNil())
+				Expect(p.IsComplete()).To(BeFalse())
+			})
+		})
+
+		Context("Transient scope", func() {
+			BeforeEach(func() {
+				p.Type = TypeOf(&Struct{})
+				mockGraph.EXPECT().Complete(gomock.Any()).Return(nil).Times(2)
+			})
+
+			It("Constructs a new instance every time", func() {
+				Expect(p.Resolve()).ToNot(BeIdenticalTo(p.Resolve()))
+			})
+		})
+
+		Context("Singleton scope", func() {
+			BeforeEach(func() {
+				p.Type = TypeOf(&Struct{})
+				p.Scope = Singleton
+				mockGraph.EXPECT().Complete(gomock.Any()).Return(nil).Times(1)
+			})
+
+			It("Constructs a single instance", func() {
+				Expect(p.Resolve()).To(BeIdenticalTo(p.Resolve()))
+				Expect(p.IsComplete()).To(BeTrue())
+			})
+		})
+	})
+})
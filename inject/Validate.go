@@ -0,0 +1,208 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const validateTag string = "validate"
+
+// Violation describes a configuration value which failed validation.
+type Violation struct {
+	Key     string
+	Message string
+}
+
+// ValidationError lists every configuration value which failed validation.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	var messages []string
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Key+" "+violation.Message)
+	}
+
+	return fmt.Sprintf("Invalid configuration: %s.", strings.Join(messages, "; "))
+}
+
+// Validate checks the validate tags of a struct, or pointer to a struct,
+// and its nested structs. The supported constraints are:
+//
+//	required      the value must not be the zero value
+//	min=N, max=N  bounds on numbers and durations, or on the length of
+//	              strings, slices and maps
+//	oneof=a b c   the value must be one of the space-separated options
+//	regex=P       strings must match P; this must be the last constraint
+//	              since the rest of the tag is taken as the pattern
+//
+// Zero values are only checked by required. The keys of violations are the
+// dotted paths of the offending fields beneath name.
+func Validate(name string, v interface{}) error {
+	var violations []Violation
+	validateValue(name, reflect.ValueOf(v), &violations)
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+func validateValue(key string, v reflect.Value, violations *[]Violation) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	typeInfo := v.Type()
+	for i := 0; i < typeInfo.NumField(); i++ {
+		fieldInfo := typeInfo.Field(i)
+		if fieldInfo.PkgPath != "" {
+			continue
+		}
+
+		fieldKey := strings.ToLower(fieldKey(fieldInfo))
+		if key != "" {
+			fieldKey = key + "." + fieldKey
+		}
+
+		if tag, ok := fieldInfo.Tag.Lookup(validateTag); ok {
+			for _, message := range checkConstraints(v.Field(i), tag) {
+				*violations = append(*violations, Violation{Key: fieldKey, Message: message})
+			}
+		}
+
+		validateValue(fieldKey, v.Field(i), violations)
+	}
+}
+
+func checkConstraints(v reflect.Value, tag string) []string {
+	var messages []string
+
+	if secret, ok := v.Interface().(Secret); ok {
+		v = reflect.ValueOf(secret.Value())
+	}
+
+	zero := isZeroValue(v)
+	for tag != "" {
+		var constraint string
+		if strings.HasPrefix(tag, "regex=") {
+			constraint, tag = tag, ""
+		} else if i := strings.Index(tag, ","); i >= 0 {
+			constraint, tag = tag[:i], tag[i+1:]
+		} else {
+			constraint, tag = tag, ""
+		}
+
+		rule, arg := constraint, ""
+		if i := strings.Index(constraint, "="); i >= 0 {
+			rule, arg = constraint[:i], constraint[i+1:]
+		}
+
+		if rule == "required" {
+			if zero {
+				messages = append(messages, "is required")
+			}
+			continue
+		}
+
+		if zero {
+			continue
+		}
+
+		if message := checkConstraint(v, rule, arg); message != "" {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages
+}
+
+func checkConstraint(v reflect.Value, rule, arg string) string {
+	switch rule {
+	case "min", "max":
+		actual, bound, ok := measure(v, arg)
+		if !ok {
+			return fmt.Sprintf("has an unsupported %s constraint (%s)", rule, arg)
+		}
+
+		if rule == "min" && actual < bound {
+			return "must be at least " + arg
+		}
+
+		if rule == "max" && actual > bound {
+			return "must be at most " + arg
+		}
+
+	case "oneof":
+		actual := fmt.Sprint(v.Interface())
+		for _, option := range strings.Fields(arg) {
+			if actual == option {
+				return ""
+			}
+		}
+
+		return "must be one of " + strings.Join(strings.Fields(arg), ", ")
+
+	case "regex":
+		r, err := regexp.Compile(arg)
+		if err != nil {
+			return fmt.Sprintf("has an invalid regex constraint (%s)", err)
+		}
+
+		if v.Kind() != reflect.String || !r.MatchString(v.String()) {
+			return "must match " + arg
+		}
+
+	default:
+		return fmt.Sprintf("has an unknown constraint (%s)", rule)
+	}
+
+	return ""
+}
+
+// measure returns the value compared by min and max constraints, along with
+// the parsed bound.
+func measure(v reflect.Value, arg string) (float64, float64, bool) {
+	if v.Type() == durationType {
+		bound, err := time.ParseDuration(arg)
+		return float64(v.Int()), float64(bound), err == nil
+	}
+
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), bound, true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), bound, true
+
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), bound, true
+
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), bound, true
+	}
+
+	return 0, 0, false
+}
+
+func isZeroValue(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
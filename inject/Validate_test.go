@@ -0,0 +1,128 @@
+package inject_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/impinj/go-inject/inject"
+	"strings"
+	"time"
+)
+
+var _ = Describe("Validate", func() {
+	type TLSConfig struct {
+		Mode string `validate:"oneof=disable require verify-full"`
+	}
+
+	type DBConfig struct {
+		Host    string        `validate:"required,regex=^[a-z0-9.-]+$"`
+		Port    int           `validate:"min=1,max=65535"`
+		Timeout time.Duration `validate:"min=1s,max=1m"`
+		Tags    []string      `validate:"max=2"`
+		TLS     TLSConfig
+	}
+
+	DescribeTable("Reports violations",
+		func(config DBConfig, expected ...Violation) {
+			err := Validate("db", &config)
+			if len(expected) == 0 {
+				Expect(err).ToNot(HaveOccurred())
+				return
+			}
+
+			Expect(err).To(BeAssignableToTypeOf(&ValidationError{}))
+			Expect(err.(*ValidationError).Violations).To(Equal(expected))
+		},
+		Entry("Valid", DBConfig{Host: "db.example.org", Port: 5432, Timeout: time.Second, TLS: TLSConfig{Mode: "require"}}),
+		Entry("Zero values are only checked by required", DBConfig{},
+			Violation{Key: "db.host", Message: "is required"}),
+		Entry("Regex", DBConfig{Host: "DB!"},
+			Violation{Key: "db.host", Message: "must match ^[a-z0-9.-]+$"}),
+		Entry("Min and max", DBConfig{Host: "db", Port: 70000, Timeout: time.Millisecond, Tags: []string{"a", "b", "c"}},
+			Violation{Key: "db.port", Message: "must be at most 65535"},
+			Violation{Key: "db.timeout", Message: "must be at least 1s"},
+			Violation{Key: "db.tags", Message: "must be at most 2"}),
+		Entry("Nested oneof", DBConfig{Host: "db", TLS: TLSConfig{Mode: "sometimes"}},
+			Violation{Key: "db.tls.mode", Message: "must be one of disable, require, verify-full"}),
+	)
+
+	It("Lists every violation in its message", func() {
+		err := Validate("db", DBConfig{Port: -1})
+		Expect(err).To(MatchError("Invalid configuration: db.host is required; db.port must be at least 1."))
+	})
+
+	Describe("Named struct injection from a source", func() {
+		type Service struct {
+			DB       DBConfig `inject:"db"`
+			Replica  DBConfig `inject:"replica"`
+			Attempts int      `inject:"attempts"`
+		}
+
+		var (
+			g       Graph
+			service Service
+		)
+
+		BeforeEach(func() {
+			service = Service{}
+		})
+
+		It("Decodes and validates the struct", func() {
+			source, err := LoadJSON(strings.NewReader(`{
+				"db": {"host": "db.example.org", "port": 5432, "timeout": "5s"},
+				"replica": {"host": "replica.example.org", "port": 5433, "timeout": "10s"},
+				"attempts": 3
+			}`))
+			Expect(err).ToNot(HaveOccurred())
+			g = NewGraph(WithSources(source))
+			g.Provide(&ValueProvider{Value: &service})
+
+			Expect(g.Resolve()).To(Succeed())
+			Expect(service.DB).To(Equal(DBConfig{Host: "db.example.org", Port: 5432, Timeout: 5 * time.Second}))
+			Expect(service.Replica.Port).To(Equal(5433))
+			Expect(service.Attempts).To(Equal(3))
+		})
+
+		It("Fails Resolve with every invalid key", func() {
+			source, err := LoadJSON(strings.NewReader(`{
+				"db": {"port": 0, "timeout": "5s"},
+				"replica": {"host": "replica.example.org", "port": 99999, "timeout": "5s"},
+				"attempts": "many"
+			}`))
+			Expect(err).ToNot(HaveOccurred())
+			g = NewGraph(WithSources(source))
+			g.Provide(&ValueProvider{Value: &service})
+
+			err = g.Resolve()
+			Expect(err).To(BeAssignableToTypeOf(&ValidationError{}))
+
+			var keys []string
+			for _, violation := range err.(*ValidationError).Violations {
+				keys = append(keys, violation.Key)
+			}
+			Expect(keys).To(ConsistOf("db.host", "replica.port", "attempts"))
+		})
+
+		It("Fails Resolve with every missing key", func() {
+			type Optional struct {
+				Service *Service `inject:""`
+				Region  string   `inject:"region,optional"`
+			}
+
+			source, err := LoadJSON(strings.NewReader(`{
+				"db": {"host": "db.example.org", "port": 5432, "timeout": "5s"}
+			}`))
+			Expect(err).ToNot(HaveOccurred())
+			g = NewGraph(WithSources(source))
+			g.Provide(&ValueProvider{Value: &service}, &ValueProvider{Value: &Optional{}})
+
+			err = g.Resolve()
+			Expect(err).To(BeAssignableToTypeOf(&ValidationError{}))
+			Expect(err.(*ValidationError).Violations).To(ConsistOf(
+				Violation{Key: "replica", Message: "is missing (checked " + source.Key("replica") + ")"},
+				Violation{Key: "attempts", Message: "is missing (checked " + source.Key("attempts") + ")"},
+			))
+		})
+	})
+})
@@ -7,6 +7,11 @@ type ValueProvider struct {
 	Complete bool
 	Context  reflect.Type
 	Value    interface{}
+	As       []reflect.Type
+}
+
+func (p ValueProvider) GetAs() []reflect.Type {
+	return p.As
 }
 
 func (p ValueProvider) GetContext() reflect.Type {
@@ -28,3 +33,20 @@ func (p ValueProvider) IsComplete() bool {
 func (p ValueProvider) Resolve() interface{} {
 	return p.Value
 }
+
+// valueProviderOf returns the value provider behind a provider, looking
+// through conditional providers, or nil if there is none.
+func valueProviderOf(provider Provider) *ValueProvider {
+	switch p := provider.(type) {
+	case *ValueProvider:
+		return p
+
+	case *ConditionalProvider:
+		return valueProviderOf(p.Provider)
+
+	case ConditionalProvider:
+		return valueProviderOf(p.Provider)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+// Command injectgen generates reflection-free wiring for a provider set. It
+// is meant to be run by go generate from the package declaring the set:
+//
+//	//go:generate injectgen -set Providers -type Server
+//
+// which writes providers_inject.go, declaring a ProvidersInjector with a
+// CompleteServer method.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/impinj/go-inject/inject/injectgen"
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	set := flag.String("set", "", "name of the package-level []inject.Provider literal to wire")
+	roots := flag.String("type", "", "comma-separated struct types to generate Complete methods for")
+	output := flag.String("output", "", "output file; defaults to <set>_inject.go")
+	flag.Parse()
+
+	if *set == "" || *roots == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *output == "" {
+		*output = strings.ToLower(*set) + "_inject.go"
+	}
+
+	if err := run(*set, strings.Split(*roots, ","), *output); err != nil {
+		fmt.Fprintln(os.Stderr, "injectgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(set string, roots []string, output string) error {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:       packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		BuildFlags: []string{"-tags=" + injectgen.BuildTag},
+	}, ".")
+	if err != nil {
+		return err
+	}
+
+	if packages.PrintErrors(pkgs) > 0 || len(pkgs) != 1 {
+		return fmt.Errorf("Could not load the package in the current directory.")
+	}
+
+	src, err := injectgen.Generate(pkgs[0], set, roots)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(output, src, 0644)
+}
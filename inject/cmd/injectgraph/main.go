@@ -0,0 +1,66 @@
+// Command injectgraph reports how the providers given to graphs in a set of
+// packages wire their inject-tagged fields, from source and without running
+// the program:
+//
+//	injectgraph ./...
+//	injectgraph -format dot ./cmd/server | dot -Tsvg > wiring.svg
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/impinj/go-inject/inject/injectgraph"
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	format := flag.String("format", "text", "output format: text or dot")
+	tags := flag.String("tags", "", "comma-separated build tags")
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	if err := run(*format, *tags, patterns); err != nil {
+		fmt.Fprintln(os.Stderr, "injectgraph:", err)
+		os.Exit(1)
+	}
+}
+
+func run(format, tags string, patterns []string) error {
+	config := &packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+	}
+	if tags != "" {
+		config.BuildFlags = []string{"-tags=" + tags}
+	}
+
+	pkgs, err := packages.Load(config, patterns...)
+	if err != nil {
+		return err
+	}
+
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("Could not load %v.", patterns)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	report := injectgraph.Analyze(pkgs, dir)
+	switch format {
+	case "text":
+		return report.WriteText(os.Stdout)
+
+	case "dot":
+		return report.WriteDot(os.Stdout)
+	}
+
+	return fmt.Errorf("Unknown format %s.", format)
+}
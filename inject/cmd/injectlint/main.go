@@ -0,0 +1,14 @@
+// Command injectlint checks inject struct tags. It can be run directly on
+// packages, or through go vet:
+//
+//	go vet -vettool=$(which injectlint) ./...
+package main
+
+import (
+	"github.com/impinj/go-inject/inject/injectlint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(injectlint.Analyzer)
+}
@@ -0,0 +1,109 @@
+// Package injectdebug serves a description of a live graph over HTTP, for
+// mounting on a service's admin port.
+package injectdebug
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/impinj/go-inject/inject"
+)
+
+// Handler serves the graph's description: JSON when the request asks for it
+// with ?format=json or an Accept header of application/json, and an HTML
+// page otherwise. The graph is described on every request. Describing a
+// graph updates its caches, so requests are described one at a time; as
+// graphs are not safe for concurrent use, nothing else should use the graph
+// while it is being served.
+func Handler(g inject.Graph) http.Handler {
+	var mutex sync.Mutex
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET", "HEAD":
+
+		default:
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+			return
+		}
+
+		mutex.Lock()
+		description := g.Describe()
+		mutex.Unlock()
+
+		if wantsJSON(r) {
+			w.Header().Set("Content-Type", "application/json")
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(description)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		page.Execute(w, description)
+	})
+}
+
+func wantsJSON(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "json"
+	}
+
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+var page = template.Must(template.New("graph").Funcs(template.FuncMap{
+	"state": state,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>Dependency graph</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+.problem { color: #c00; }
+</style>
+</head>
+<body>
+<h1>Dependency graph</h1>
+<p><a href="?format=json">JSON</a></p>
+<table>
+<tr><th>ID</th><th>Kind</th><th>Type</th><th>Name</th><th>Context</th><th>State</th><th>Dependencies</th></tr>
+{{range .Providers}}<tr id="p{{.ID}}">
+<td>{{.ID}}</td><td>{{.Kind}}</td><td>{{.Type}}</td><td>{{.Name}}</td><td>{{.Context}}</td><td>{{state .}}</td>
+<td>{{template "dependencies" .Dependencies}}</td>
+</tr>
+{{end}}</table>
+{{if .Completed}}<h2>Completed structs</h2>
+<table>
+<tr><th>Type</th><th>Dependencies</th></tr>
+{{range .Completed}}<tr>
+<td>{{.Type}}</td><td>{{template "dependencies" .Dependencies}}</td>
+</tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+{{define "dependencies"}}{{range .}}<div>{{.Field}} ({{.Type}}{{if .Name}} named {{.Name}}{{end}}{{if .Optional}}, optional{{end}}):
+{{if .Provider}}<a href="#p{{.Provider}}">provider {{.Provider}}</a>{{else if .Source}}{{.Source}}{{else if .JustInTime}}just in time{{else}}<span class="problem">{{.Problem}}</span>{{end}}{{if .Observed}} (used){{end}}</div>
+{{end}}{{end}}`))
+
+// state describes where a provider is in its lifecycle.
+func state(p inject.ProviderDescription) string {
+	switch {
+	case p.Singleton && p.Complete:
+		return "instantiated singleton"
+
+	case p.Singleton:
+		return "singleton"
+
+	case p.Complete:
+		return "complete"
+	}
+
+	return "transient"
+}
@@ -0,0 +1,143 @@
+package injectdebug_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"github.com/impinj/go-inject/inject"
+	"github.com/impinj/go-inject/inject/injectdebug"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct{}
+
+func (English) Greet() string { return "hello" }
+
+type Service struct {
+	Greeter Greeter `inject:""`
+	Missing Greeter `inject:"missing,optional"`
+}
+
+var _ = Describe("Handler", func() {
+	var (
+		g      inject.Graph
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		g = inject.NewGraph()
+		g.Provide(
+			&inject.ValueProvider{Value: &Service{}},
+			&inject.TypeProvider{
+				Type:           reflect.TypeOf(English{}),
+				Scope:          inject.Singleton,
+				ResolveContext: g,
+				As:             []reflect.Type{reflect.TypeOf((*Greeter)(nil)).Elem()},
+			})
+
+		server = httptest.NewServer(injectdebug.Handler(g))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func(path, accept string) *http.Response {
+		req, err := http.NewRequest("GET", server.URL+path, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		return resp
+	}
+
+	It("Serves the description as JSON", func() {
+		for _, resp := range []*http.Response{get("/?format=json", ""), get("/", "application/json")} {
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			var description inject.Description
+			Expect(json.NewDecoder(resp.Body).Decode(&description)).To(Succeed())
+			Expect(description.Providers).To(HaveLen(2))
+			Expect(description.Providers[1].Kind).To(Equal("TypeProvider"))
+			Expect(*description.Providers[0].Dependencies[0].Provider).To(Equal(1))
+			Expect(description.Providers[0].Dependencies[1].Problem).To(Equal("missing"))
+		}
+	})
+
+	It("Serves an HTML page", func() {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		server.Config.Handler.ServeHTTP(rec, req)
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(HavePrefix("text/html"))
+		Expect(rec.Body.String()).To(ContainSubstring("*injectdebug_test.Service"))
+		Expect(rec.Body.String()).To(ContainSubstring(`<a href="#p1">provider 1</a>`))
+		Expect(rec.Body.String()).To(ContainSubstring(`<span class="problem">missing</span>`))
+		Expect(rec.Body.String()).To(ContainSubstring("singleton"))
+	})
+
+	It("Shows which singletons have been instantiated", func() {
+		Expect(g.Resolve()).To(Succeed())
+
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		server.Config.Handler.ServeHTTP(rec, req)
+
+		Expect(rec.Body.String()).To(ContainSubstring("<td>instantiated singleton</td>"))
+	})
+
+	It("Shows structs completed directly", func() {
+		type Consumer struct {
+			Greeter Greeter `inject:""`
+		}
+
+		var c Consumer
+		Expect(g.Complete(&c)).To(Succeed())
+
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		server.Config.Handler.ServeHTTP(rec, req)
+
+		Expect(rec.Body.String()).To(ContainSubstring("<td>injectdebug_test.Consumer</td>"))
+		Expect(rec.Body.String()).To(ContainSubstring(`<a href="#p1">provider 1</a> (used)`))
+	})
+
+	It("Serves parallel requests", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				rec := httptest.NewRecorder()
+				req, _ := http.NewRequest("GET", "/?format=json", nil)
+				server.Config.Handler.ServeHTTP(rec, req)
+				Expect(rec.Code).To(Equal(http.StatusOK))
+			}()
+		}
+		wg.Wait()
+	})
+
+	It("Rejects other methods", func() {
+		resp, err := http.Post(server.URL, "text/plain", nil)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
@@ -0,0 +1,13 @@
+package injectdebug_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestInjectdebug(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Injectdebug Suite")
+}
@@ -0,0 +1,480 @@
+// Package injectgen generates plain Go code which wires a provider set the
+// way a graph would, without reflection. Bindings which are missing or
+// ambiguous are reported when generating rather than when the program runs.
+package injectgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/impinj/go-inject/inject/internal/wiring"
+	"golang.org/x/tools/go/packages"
+)
+
+// BuildTag excludes generated files from the build while generating, so
+// that a stale file cannot stop its package from loading.
+const BuildTag = "injectgen"
+
+// Generate returns the source of a file for pkg which wires set, a
+// package-level []inject.Provider literal, and adds a Complete method to the
+// generated injector for each of roots, which name struct types in pkg.
+func Generate(pkg *packages.Package, set string, roots []string) ([]byte, error) {
+	g := &generator{
+		pkg:        pkg,
+		imports:    map[string]string{},
+		provided:   map[int]bool{},
+		completers: map[string]int{},
+	}
+
+	if err := g.loadSet(set); err != nil {
+		return nil, err
+	}
+
+	injector := set + "Injector"
+	constructor := "New" + injector
+	if !ast.IsExported(set) {
+		constructor = "new" + strings.ToUpper(injector[:1]) + injector[1:]
+	}
+
+	var methods bytes.Buffer
+	for _, root := range roots {
+		obj, ok := pkg.Types.Scope().Lookup(root).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("Could not find type %s in %s.", root, pkg.PkgPath)
+		}
+
+		if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+			return nil, fmt.Errorf("Cannot complete %s, which is not a struct.", root)
+		}
+
+		fmt.Fprintf(&methods, "\n// Complete%s sets the inject-tagged fields of v.\n", root)
+		fmt.Fprintf(&methods, "func (i *%s) Complete%s(v *%s) {\n\ti.%s(v)\n}\n",
+			injector,
+			root,
+			root,
+			g.completer(obj.Type()))
+	}
+
+	for len(g.queue) > 0 {
+		work := g.queue[0]
+		g.queue = g.queue[1:]
+		work(&methods, injector)
+	}
+
+	if len(g.errs) > 0 {
+		return nil, fmt.Errorf("Could not wire %s:\n\t%s", set, strings.Join(g.errs, "\n\t"))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by injectgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "//go:build !%s\n\n", BuildTag)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg.Name)
+
+	if len(g.imports) > 0 {
+		var paths []string
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		buf.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&buf, "\t%s %s\n", g.imports[path], strconv.Quote(path))
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	fmt.Fprintf(&buf, "// %s wires the providers in %s without reflection.\n", injector, set)
+	fmt.Fprintf(&buf, "// It is not safe for concurrent use.\n")
+	fmt.Fprintf(&buf, "type %s struct {\n", injector)
+	for k, binding := range g.bindings {
+		if g.provided[k] && binding.Singleton {
+			fmt.Fprintf(&buf, "\tvalue%d %s\n\tdone%d bool\n", k, g.typeString(binding.Type), k)
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(&buf, "func %s() *%s {\n\treturn &%s{}\n}\n", constructor, injector, injector)
+	buf.Write(methods.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+type generator struct {
+	pkg      *packages.Package
+	bindings []*wiring.Binding
+	imports  map[string]string
+	errs     []string
+
+	// provided records the bindings whose provide methods are generated,
+	// and completers the struct types whose complete methods are.
+	provided   map[int]bool
+	completers map[string]int
+	queue      []func(buf *bytes.Buffer, injector string)
+}
+
+func (g *generator) loadSet(set string) error {
+	for _, file := range g.pkg.Syntax {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for i, name := range spec.Names {
+					if name.Name != set || i >= len(spec.Values) {
+						continue
+					}
+
+					lit, ok := ast.Unparen(spec.Values[i]).(*ast.CompositeLit)
+					if !ok {
+						return fmt.Errorf("%s: %s must be a []inject.Provider literal.", g.position(name.Pos()), set)
+					}
+
+					for _, elt := range lit.Elts {
+						binding, err := wiring.ParseProvider(g.pkg.TypesInfo, elt)
+						if err != nil {
+							g.errs = append(g.errs, fmt.Sprintf("%s: %s", g.position(elt.Pos()), err))
+							continue
+						}
+						g.bindings = append(g.bindings, binding)
+					}
+
+					return nil
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("Could not find a provider set named %s in %s.", set, g.pkg.PkgPath)
+}
+
+// provider returns the name of the method providing binding k, generating
+// it if needed.
+func (g *generator) provider(k int) string {
+	name := fmt.Sprintf("provide%d", k)
+	if g.provided[k] {
+		return name
+	}
+	g.provided[k] = true
+
+	binding := g.bindings[k]
+	g.queue = append(g.queue, func(buf *bytes.Buffer, injector string) {
+		var body bytes.Buffer
+		result := g.typeString(binding.Type)
+
+		switch binding.Kind {
+		case "ValueProvider":
+			if !g.literal(binding.Value) {
+				g.errs = append(g.errs, fmt.Sprintf("%s: Cannot generate ValueProvider.Value, which is neither a constant nor a composite literal and would be evaluated again.", g.position(binding.Value.Pos())))
+			}
+			fmt.Fprintf(&body, "\tif !i.done%d {\n\t\ti.value%d, i.done%d = %s, true\n", k, k, k, g.expr(binding.Value))
+			if completer, ok := g.nestedCompleter(binding.Type); ok {
+				fmt.Fprintf(&body, "\t\tif %s {\n\t\t\ti.%s(i.value%d)\n\t\t}\n", g.incomplete(binding.Type, fmt.Sprintf("i.value%d", k)), completer, k)
+			}
+			fmt.Fprintf(&body, "\t}\n\treturn i.value%d\n", k)
+
+		case "BuilderProvider":
+			if binding.Singleton {
+				fmt.Fprintf(&body, "\tif i.done%d {\n\t\treturn i.value%d\n\t}\n", k, k)
+			}
+
+			args := g.builderArgs(&body, binding)
+			fmt.Fprintf(&body, "\tv := %s(%s)\n", g.expr(binding.Builder), strings.Join(args, ", "))
+
+			if binding.Singleton {
+				fmt.Fprintf(&body, "\ti.value%d, i.done%d = v, true\n", k, k)
+			}
+			body.WriteString("\treturn v\n")
+
+		case "TypeProvider":
+			if binding.Singleton {
+				fmt.Fprintf(&body, "\tif i.done%d {\n\t\treturn i.value%d\n\t}\n", k, k)
+			}
+
+			if ptr, ok := binding.Type.(*types.Pointer); ok {
+				fmt.Fprintf(&body, "\tv := new(%s)\n", g.typeString(ptr.Elem()))
+				if binding.Singleton {
+					// Pointer singletons are shared before they are completed
+					// so that cycles through them resolve to the same instance.
+					fmt.Fprintf(&body, "\ti.value%d, i.done%d = v, true\n", k, k)
+				}
+				fmt.Fprintf(&body, "\ti.%s(v)\n\treturn v\n", g.completer(ptr.Elem()))
+			} else {
+				fmt.Fprintf(&body, "\tv := new(%s)\n\ti.%s(v)\n", result, g.completer(binding.Type))
+				if binding.Singleton {
+					fmt.Fprintf(&body, "\ti.value%d, i.done%d = *v, true\n", k, k)
+				}
+				body.WriteString("\treturn *v\n")
+			}
+		}
+
+		position := g.position(binding.Pos)
+		fmt.Fprintf(buf, "\n// %s provides %s from the %s at %s:%d.\n",
+			name,
+			result,
+			binding.Kind,
+			filepath.Base(position.Filename),
+			position.Line)
+		fmt.Fprintf(buf, "func (i *%s) %s() %s {\n%s}\n", injector, name, result, body.String())
+	})
+
+	return name
+}
+
+// builderArgs writes the statements preparing a builder's arguments and
+// returns the expressions passing them.
+func (g *generator) builderArgs(body *bytes.Buffer, binding *wiring.Binding) []string {
+	signature := binding.Signature
+
+	var args []string
+	for j := 0; j < signature.Params().Len(); j++ {
+		param := signature.Params().At(j).Type()
+		arg := fmt.Sprintf("arg%d", j)
+
+		switch {
+		case wiring.IsParamObject(param):
+			fmt.Fprintf(body, "\tvar %s %s\n", arg, g.typeString(param))
+			for _, field := range wiring.Fields(param) {
+				g.assign(body, arg, field, false)
+			}
+
+		case isStruct(param):
+			fmt.Fprintf(body, "\tvar %s %s\n\ti.%s(&%s)\n", arg, g.typeString(param), g.completer(param), arg)
+
+		default:
+			k, ok := g.choose(binding.Pos, fmt.Sprintf("parameter %d of %s", j, g.expr(binding.Builder)), param, nil, "", false)
+			if !ok {
+				continue
+			}
+			arg = "i." + g.provider(k) + "()"
+		}
+
+		args = append(args, arg)
+	}
+
+	return args
+}
+
+// completer returns the name of the method completing a struct type,
+// generating it if needed.
+func (g *generator) completer(typ types.Type) string {
+	key := types.TypeString(typ, nil)
+	if n, ok := g.completers[key]; ok {
+		return fmt.Sprintf("complete%d", n)
+	}
+
+	n := len(g.completers)
+	g.completers[key] = n
+	name := fmt.Sprintf("complete%d", n)
+
+	g.queue = append(g.queue, func(buf *bytes.Buffer, injector string) {
+		var body bytes.Buffer
+		for _, field := range wiring.Fields(typ) {
+			g.assign(&body, "v", field, true)
+		}
+
+		fmt.Fprintf(buf, "\nfunc (i *%s) %s(v *%s) {\n%s}\n", injector, name, g.typeString(typ), body.String())
+	})
+
+	return name
+}
+
+// assign writes the statement setting a field of target, and completing
+// the value set if it is an incomplete struct and nested is true.
+func (g *generator) assign(body *bytes.Buffer, target string, field wiring.Field, nested bool) {
+	if !field.Var.Exported() {
+		g.errs = append(g.errs, fmt.Sprintf("%s: Cannot set a field (%s), which is unexported.", g.position(field.Pos()), field.Path()))
+		return
+	}
+
+	k, ok := g.choose(field.Pos(), field.Path(), field.Var.Type(), field.Owner, field.Name, field.Optional)
+	if !ok {
+		return
+	}
+
+	lhs := target + "." + field.Var.Name()
+	fmt.Fprintf(body, "\t%s = i.%s()\n", lhs, g.provider(k))
+
+	if completer, ok := g.nestedCompleter(field.Var.Type()); ok && nested {
+		fmt.Fprintf(body, "\tif %s != nil && %s {\n\t\ti.%s(%s)\n\t}\n", lhs, g.incomplete(field.Var.Type(), lhs), completer, lhs)
+	}
+}
+
+// choose selects the binding for a request, recording an error unless
+// exactly one matches or the request is optional and none does.
+func (g *generator) choose(pos token.Pos, what string, typ, context types.Type, name string, optional bool) (int, bool) {
+	candidates := wiring.Select(g.bindings, typ, context, name)
+
+	request := g.typeString(typ)
+	if name != "" {
+		request += " named " + name
+	}
+
+	switch {
+	case len(candidates) == 1:
+		for k, binding := range g.bindings {
+			if binding == candidates[0] {
+				return k, true
+			}
+		}
+
+	case len(candidates) > 1:
+		var positions []string
+		for _, candidate := range candidates {
+			positions = append(positions, g.position(candidate.Pos).String())
+		}
+		g.errs = append(g.errs, fmt.Sprintf("%s: Found multiple bindings for %s in %s (%s).",
+			g.position(pos),
+			request,
+			what,
+			strings.Join(positions, ", ")))
+
+	case !optional:
+		g.errs = append(g.errs, fmt.Sprintf("%s: Could not find a binding for %s in %s.", g.position(pos), request, what))
+	}
+
+	return 0, false
+}
+
+// nestedCompleter returns the completer for values of a pointer-to-struct
+// type which the graph would complete after injecting them: those with
+// inject-tagged pointer or interface fields.
+func (g *generator) nestedCompleter(typ types.Type) (string, bool) {
+	ptr, ok := typ.(*types.Pointer)
+	if !ok || !isStruct(ptr.Elem()) || len(nillableFields(ptr.Elem())) == 0 {
+		return "", false
+	}
+
+	return g.completer(ptr.Elem()), true
+}
+
+// incomplete returns an expression reporting whether v, a pointer to a
+// struct, has nil inject-tagged fields, as the graph's isComplete does.
+func (g *generator) incomplete(typ types.Type, v string) string {
+	var checks []string
+	for _, field := range nillableFields(typ.(*types.Pointer).Elem()) {
+		checks = append(checks, v+"."+field.Var.Name()+" == nil")
+	}
+
+	return "(" + strings.Join(checks, " || ") + ")"
+}
+
+func nillableFields(typ types.Type) []wiring.Field {
+	var fields []wiring.Field
+	for _, field := range wiring.Fields(typ) {
+		switch field.Var.Type().Underlying().(type) {
+		case *types.Pointer, *types.Interface:
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+func isStruct(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Struct)
+	return ok
+}
+
+// typeString formats a type as it is written in the generated file,
+// importing the packages it refers to.
+func (g *generator) typeString(typ types.Type) string {
+	return types.TypeString(typ, func(pkg *types.Package) string {
+		if pkg == g.pkg.Types {
+			return ""
+		}
+
+		return g.importName(pkg.Path(), pkg.Name())
+	})
+}
+
+// expr formats an expression from the package's source, importing the
+// packages it refers to under the names it uses.
+func (g *generator) expr(expr ast.Expr) string {
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				if pkgName, ok := g.pkg.TypesInfo.Uses[ident].(*types.PkgName); ok {
+					g.imports[pkgName.Imported().Path()] = ident.Name
+				}
+			}
+		}
+
+		return true
+	})
+
+	var buf bytes.Buffer
+	printer.Fprint(&buf, g.pkg.Fset, expr)
+	return buf.String()
+}
+
+func (g *generator) importName(path, name string) string {
+	if existing, ok := g.imports[path]; ok {
+		return existing
+	}
+
+	unique := name
+	for n := 2; g.importNameTaken(unique); n++ {
+		unique = name + strconv.Itoa(n)
+	}
+
+	g.imports[path] = unique
+	return unique
+}
+
+func (g *generator) importNameTaken(name string) bool {
+	for _, existing := range g.imports {
+		if existing == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// literal reports whether expr can be copied into the generated code without
+// changing what it evaluates to: a constant, nil, or a composite literal, or
+// pointer to one, whose elements are themselves literal.
+func (g *generator) literal(expr ast.Expr) bool {
+	expr = ast.Unparen(expr)
+	if tv := g.pkg.TypesInfo.Types[expr]; tv.Value != nil || tv.IsNil() {
+		return true
+	}
+
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = ast.Unparen(unary.X)
+	}
+
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+
+		if !g.literal(elt) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (g *generator) position(pos token.Pos) token.Position {
+	return g.pkg.Fset.Position(pos)
+}
@@ -0,0 +1,118 @@
+package injectgen_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/impinj/go-inject/inject/injectgen"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+)
+
+var _ = Describe("Generate", func() {
+	var (
+		pkg *packages.Package
+	)
+
+	mode := packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo
+
+	load := func(overlay map[string][]byte) *packages.Package {
+		pkgs, err := packages.Load(&packages.Config{Mode: mode, Overlay: overlay}, "./testdata/wired")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkgs).To(HaveLen(1))
+		return pkgs[0]
+	}
+
+	BeforeEach(func() {
+		pkg = load(nil)
+		Expect(pkg.Errors).To(BeEmpty())
+	})
+
+	It("Generates code which compiles with its package", func() {
+		src, err := injectgen.Generate(pkg, "Providers", []string{"Service", "Loop"})
+		Expect(err).NotTo(HaveOccurred())
+
+		path, err := filepath.Abs("testdata/wired/providers_inject.go")
+		Expect(err).NotTo(HaveOccurred())
+
+		generated := load(map[string][]byte{path: src})
+		Expect(generated.Errors).To(BeEmpty())
+		Expect(generated.Types.Scope().Lookup("NewProvidersInjector")).NotTo(BeNil())
+	})
+
+	It("Wires fields, builders and singletons", func() {
+		src, err := injectgen.Generate(pkg, "Providers", []string{"Service"})
+		Expect(err).NotTo(HaveOccurred())
+
+		code := string(src)
+		Expect(code).To(HavePrefix("// Code generated by injectgen. DO NOT EDIT.\n\n//go:build !injectgen\n"))
+		Expect(code).To(ContainSubstring("func (i *ProvidersInjector) CompleteService(v *Service) {"))
+		Expect(code).To(ContainSubstring("v.Greeter = i.provide2()"))
+		Expect(code).To(ContainSubstring("arg0.Config = i.provide0()\n\tv := NewClient(arg0)"))
+		Expect(code).To(ContainSubstring("if v.Loop != nil && (v.Loop.Service == nil) {"))
+		Expect(code).To(ContainSubstring("\tv := new(Loop)\n\ti.value3, i.done3 = v, true\n"))
+
+		// Optional and unused bindings are left out.
+		Expect(code).NotTo(ContainSubstring("Label"))
+		Expect(code).NotTo(ContainSubstring("provide5"))
+	})
+
+	It("Selects bindings by context", func() {
+		src, err := injectgen.Generate(pkg, "Contexts", []string{"Contextual"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(src)).To(ContainSubstring("v.Greeter = i.provide1()"))
+		Expect(string(src)).To(ContainSubstring("i.value1, i.done1 = English{Punctuation: \"?\"}, true"))
+	})
+
+	It("Binds values by their dynamic type", func() {
+		src, err := injectgen.Generate(pkg, "Converted", []string{"Speaker"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(src)).To(ContainSubstring("v.English = i.provide0()"))
+		Expect(string(src)).To(ContainSubstring("i.value0, i.done0 = English{Punctuation: \".\"}, true"))
+	})
+
+	It("Passes bindings to builder parameters of any type", func() {
+		src, err := injectgen.Generate(pkg, "Reports", []string{"Reporter"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(src)).To(ContainSubstring("v := NewReport(i.provide0())"))
+	})
+
+	It("Constructs transient bindings every time", func() {
+		src, err := injectgen.Generate(pkg, "Transients", []string{"Fresh"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(src)).To(ContainSubstring("\tv := new(Config)\n"))
+		Expect(string(src)).NotTo(ContainSubstring("i.done0"))
+	})
+
+	It("Reports missing and ambiguous bindings", func() {
+		_, err := injectgen.Generate(pkg, "Broken", []string{"Service"})
+		Expect(err).To(HaveOccurred())
+
+		Expect(err.Error()).To(ContainSubstring("Could not wire Broken:"))
+		Expect(err.Error()).To(MatchRegexp(`wired.go:\d+:\d+: Found multiple bindings for Greeter in Service.Greeter \(.*wired.go:\d+:\d+, .*wired.go:\d+:\d+\)\.`))
+		Expect(err.Error()).To(ContainSubstring("Could not find a binding for *Client in Service.Client."))
+		Expect(err.Error()).NotTo(ContainSubstring("Service.Label"))
+	})
+
+	It("Reports unexported fields", func() {
+		_, err := injectgen.Generate(pkg, "Converted", []string{"Hidden"})
+		Expect(err).To(MatchError(MatchRegexp(`wired.go:\d+:\d+: Cannot set a field \(Hidden.greeter\), which is unexported\.`)))
+	})
+
+	It("Reports values which would be evaluated again", func() {
+		_, err := injectgen.Generate(pkg, "Pools", []string{"Pooled"})
+		Expect(err).To(MatchError(MatchRegexp(`wired.go:\d+:\d+: Cannot generate ValueProvider.Value, which is neither a constant nor a composite literal`)))
+	})
+
+	It("Reports unknown sets and types", func() {
+		_, err := injectgen.Generate(pkg, "Missing", []string{"Service"})
+		Expect(err).To(MatchError(ContainSubstring("Could not find a provider set named Missing")))
+
+		_, err = injectgen.Generate(pkg, "Providers", []string{"Greeter"})
+		Expect(err).To(MatchError("Cannot complete Greeter, which is not a struct."))
+	})
+})
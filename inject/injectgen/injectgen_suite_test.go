@@ -0,0 +1,13 @@
+package injectgen_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestInjectgen(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Injectgen Suite")
+}
@@ -0,0 +1,131 @@
+package wired
+
+import (
+	"reflect"
+
+	"github.com/impinj/go-inject/inject"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct {
+	Punctuation string
+}
+
+func (e English) Greet() string {
+	return "hello" + e.Punctuation
+}
+
+type Config struct {
+	URL string
+}
+
+type Client struct {
+	Config *Config
+}
+
+type ClientParams struct {
+	inject.In
+	Config  *Config `inject:""`
+	Timeout int     `inject:"timeout,optional"`
+}
+
+func NewClient(p ClientParams) *Client {
+	return &Client{Config: p.Config}
+}
+
+type Loop struct {
+	Service *Service `inject:""`
+}
+
+type Service struct {
+	Greeter Greeter `inject:""`
+	Client  *Client `inject:""`
+	Loop    *Loop   `inject:""`
+	Label   string  `inject:"label,optional"`
+}
+
+var Providers = []inject.Provider{
+	&inject.ValueProvider{Value: &Config{URL: "http://example.com"}},
+	&inject.BuilderProvider{Builder: NewClient},
+	&inject.SingletonProvider{Provider: &inject.BuilderProvider{
+		Builder: func() Greeter { return English{Punctuation: "!"} },
+	}},
+	&inject.TypeProvider{Type: reflect.TypeOf(&Loop{}), Scope: inject.Singleton},
+	&inject.TypeProvider{Type: reflect.TypeOf(&Service{}), Scope: inject.Singleton},
+	&inject.ValueProvider{Name: "unused", Value: 42},
+}
+
+type Contextual struct {
+	Greeter Greeter `inject:""`
+}
+
+var Contexts = []inject.Provider{
+	&inject.ValueProvider{Value: Greeter(English{Punctuation: "."})},
+	&inject.ValueProvider{
+		Value:   English{Punctuation: "?"},
+		Context: reflect.TypeOf(Contextual{}),
+		As:      []reflect.Type{reflect.TypeOf((*Greeter)(nil)).Elem()},
+	},
+}
+
+type Speaker struct {
+	English English `inject:""`
+}
+
+var Converted = []inject.Provider{
+	&inject.ValueProvider{Value: Greeter(English{Punctuation: "."})},
+}
+
+type Fresh struct {
+	Config *Config `inject:""`
+}
+
+var Transients = []inject.Provider{
+	&inject.TypeProvider{Type: reflect.TypeOf(&Config{}), Scope: inject.Transient},
+}
+
+var Broken = []inject.Provider{
+	&inject.ValueProvider{Value: English{}},
+	&inject.ValueProvider{Value: English{Punctuation: "!"}},
+	&inject.TypeProvider{Type: reflect.TypeOf(&Loop{}), Scope: inject.Singleton},
+}
+
+type Hidden struct {
+	greeter Greeter `inject:""`
+}
+
+type Pool struct {
+	Size int
+}
+
+func NewPool() *Pool {
+	return &Pool{Size: 10}
+}
+
+type Pooled struct {
+	Pool *Pool `inject:""`
+}
+
+var Pools = []inject.Provider{
+	&inject.ValueProvider{Value: NewPool()},
+}
+
+type Report struct {
+	Config *Config
+}
+
+func NewReport(c *Config) *Report {
+	return &Report{Config: c}
+}
+
+type Reporter struct {
+	Report *Report `inject:""`
+}
+
+var Reports = []inject.Provider{
+	&inject.ValueProvider{Value: &Config{URL: "http://example.com"}},
+	&inject.BuilderProvider{Builder: NewReport},
+}
@@ -0,0 +1,442 @@
+// Package injectgraph describes, from source, the providers a program gives
+// its graphs and how their inject-tagged fields would be satisfied, without
+// running the program.
+//
+// Providers are evaluated from composite literals and calls to inject.Bind.
+// Overrides are applied once every Provide call has been seen, replacing the
+// bindings they stand in for as Graph.Override does.
+package injectgraph
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/impinj/go-inject/inject/internal/wiring"
+	"golang.org/x/tools/go/packages"
+)
+
+// Report describes the bindings given to Provide and Override calls in a
+// set of packages, and the inject-tagged structs which no binding provides.
+// All calls are treated as providing to the same graph.
+type Report struct {
+	Nodes []Node
+
+	// Unevaluated lists providers which could not be evaluated statically,
+	// such as those returned by function calls.
+	Unevaluated []string
+
+	// Unmatched lists overrides which replace no binding, for which
+	// Graph.Override would fail.
+	Unmatched []string
+}
+
+// Node is a binding, or an inject-tagged struct completed directly.
+type Node struct {
+	ID           int
+	Kind         string
+	Type         string
+	Name         string
+	Context      string
+	Position     string
+	Dependencies []Edge
+}
+
+// Edge is a dependency of a node and the node which satisfies it. Target is
+// -1 if none does, in which case Problem says why.
+type Edge struct {
+	Field    string
+	Type     string
+	Name     string
+	Optional bool
+	Target   int
+	Problem  string
+}
+
+type analysis struct {
+	dir       string
+	fset      *token.FileSet
+	specs     map[types.Object]setSpec
+	bindings  []*wiring.Binding
+	overrides []*wiring.Binding
+	seen      map[token.Pos]bool
+	report    *Report
+}
+
+// setSpec is the literal a package-level variable is initialized with.
+type setSpec struct {
+	lit  *ast.CompositeLit
+	info *types.Info
+}
+
+// Analyze builds a report from packages loaded with syntax and type
+// information. Positions are given relative to dir where possible.
+func Analyze(pkgs []*packages.Package, dir string) *Report {
+	a := &analysis{
+		dir:    dir,
+		specs:  map[types.Object]setSpec{},
+		seen:   map[token.Pos]bool{},
+		report: &Report{},
+	}
+
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if a.fset == nil {
+			a.fset = pkg.Fset
+		}
+		a.indexSets(pkg)
+	})
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(node ast.Node) bool {
+				if call, ok := node.(*ast.CallExpr); ok {
+					switch graphMethod(pkg.TypesInfo, call) {
+					case "Provide":
+						a.addCall(pkg.TypesInfo, call, false)
+
+					case "Override":
+						a.addCall(pkg.TypesInfo, call, true)
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	for _, override := range a.overrides {
+		a.override(override)
+	}
+
+	for k, binding := range a.bindings {
+		a.report.Nodes = append(a.report.Nodes, a.node(k, binding.Kind, binding.Type, binding.Name, binding.Context, binding.Pos, wiring.Dependencies(binding)))
+	}
+
+	for _, pkg := range pkgs {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || obj.IsAlias() || len(wiring.Fields(obj.Type())) == 0 || a.bound(obj.Type()) {
+				continue
+			}
+
+			a.report.Nodes = append(a.report.Nodes, a.node(len(a.report.Nodes), "struct", obj.Type(), "", nil, obj.Pos(), wiring.FieldDependencies(obj.Type())))
+		}
+	}
+
+	return a.report
+}
+
+func (a *analysis) indexSets(pkg *packages.Package) {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for i, name := range spec.Names {
+					if i >= len(spec.Values) {
+						continue
+					}
+
+					if lit, ok := ast.Unparen(spec.Values[i]).(*ast.CompositeLit); ok {
+						a.specs[pkg.TypesInfo.Defs[name]] = setSpec{lit, pkg.TypesInfo}
+					}
+				}
+			}
+		}
+	}
+}
+
+// graphMethod returns the name of the inject package's method a call
+// invokes, or "" if it invokes none.
+func graphMethod(info *types.Info, call *ast.CallExpr) string {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	fn, ok := info.Uses[selector.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != wiring.InjectPath {
+		return ""
+	}
+
+	return fn.Name()
+}
+
+func (a *analysis) addCall(info *types.Info, call *ast.CallExpr, override bool) {
+	if !call.Ellipsis.IsValid() {
+		for _, arg := range call.Args {
+			a.addProvider(info, arg, override)
+		}
+		return
+	}
+
+	arg := ast.Unparen(call.Args[len(call.Args)-1])
+	if lit, ok := arg.(*ast.CompositeLit); ok {
+		for _, elt := range lit.Elts {
+			a.addProvider(info, elt, override)
+		}
+		return
+	}
+
+	if ident, ok := arg.(*ast.Ident); ok {
+		if spec, ok := a.specs[info.Uses[ident]]; ok {
+			for _, elt := range spec.lit.Elts {
+				a.addProvider(spec.info, elt, override)
+			}
+			return
+		}
+	}
+
+	a.unevaluated(arg.Pos(), "Cannot evaluate providers statically; use a literal or a package-level variable.")
+}
+
+func (a *analysis) addProvider(info *types.Info, expr ast.Expr, override bool) {
+	if a.seen[expr.Pos()] {
+		return
+	}
+	a.seen[expr.Pos()] = true
+
+	binding, err := wiring.ParseProvider(info, expr)
+	if err != nil {
+		a.unevaluated(expr.Pos(), err.Error())
+		return
+	}
+
+	if override {
+		a.overrides = append(a.overrides, binding)
+		return
+	}
+
+	a.bindings = append(a.bindings, binding)
+}
+
+// override puts an override in place of the first binding it stands in for
+// and removes the others.
+func (a *analysis) override(override *wiring.Binding) {
+	var kept []*wiring.Binding
+	replaced := false
+	for _, binding := range a.bindings {
+		switch {
+		case !wiring.CanOverride(override, binding):
+			kept = append(kept, binding)
+
+		case !replaced:
+			kept = append(kept, override)
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		a.report.Unmatched = append(a.report.Unmatched, a.position(override.Pos)+": Override replaces no binding.")
+		return
+	}
+
+	a.bindings = kept
+}
+
+func (a *analysis) unevaluated(pos token.Pos, reason string) {
+	a.report.Unevaluated = append(a.report.Unevaluated, a.position(pos)+": "+reason)
+}
+
+// bound reports whether a binding provides typ, or a pointer to it, either
+// as its own type or as one of the types it was declared as.
+func (a *analysis) bound(typ types.Type) bool {
+	for _, binding := range a.bindings {
+		for _, provided := range append([]types.Type{binding.Type}, binding.As...) {
+			if ptr, ok := provided.(*types.Pointer); ok {
+				provided = ptr.Elem()
+			}
+
+			if types.Identical(provided, typ) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (a *analysis) node(id int, kind string, typ types.Type, name string, context types.Type, pos token.Pos, deps []wiring.Dependency) Node {
+	node := Node{
+		ID:       id,
+		Kind:     kind,
+		Type:     typeString(typ),
+		Name:     name,
+		Context:  typeString(context),
+		Position: a.position(pos),
+	}
+
+	for _, dep := range deps {
+		edge := Edge{
+			Field:    dep.Path,
+			Type:     typeString(dep.Type),
+			Name:     dep.Name,
+			Optional: dep.Optional,
+			Target:   -1,
+		}
+
+		candidates := wiring.Select(a.bindings, dep.Type, dep.Context, dep.Name)
+		switch {
+		case len(candidates) == 1:
+			for k, binding := range a.bindings {
+				if binding == candidates[0] {
+					edge.Target = k
+				}
+			}
+
+		case len(candidates) > 1:
+			edge.Problem = fmt.Sprintf("%d bindings", len(candidates))
+
+		case dep.Name != "":
+			edge.Problem = "unbound (may come from a source)"
+
+		default:
+			edge.Problem = "missing"
+		}
+
+		node.Dependencies = append(node.Dependencies, edge)
+	}
+
+	return node
+}
+
+func (a *analysis) position(pos token.Pos) string {
+	position := a.fset.Position(pos)
+	if rel, err := filepath.Rel(a.dir, position.Filename); err == nil {
+		position.Filename = rel
+	}
+
+	return position.String()
+}
+
+func typeString(typ types.Type) string {
+	if typ == nil {
+		return ""
+	}
+
+	return types.TypeString(typ, func(pkg *types.Package) string {
+		return pkg.Name()
+	})
+}
+
+// WriteText writes the report as an indented list of nodes, each followed
+// by its dependencies and what satisfies them.
+func (r *Report) WriteText(w io.Writer) error {
+	var buf bytes.Buffer
+	for _, node := range r.Nodes {
+		fmt.Fprintf(&buf, "[%d] %s\n", node.ID, describeNode(node, " "))
+		fmt.Fprintf(&buf, "\tat %s\n", node.Position)
+
+		for _, edge := range node.Dependencies {
+			fmt.Fprintf(&buf, "\t%s %s -> ", edge.Field, describeEdge(edge))
+			if edge.Target >= 0 {
+				fmt.Fprintf(&buf, "[%d] %s\n", edge.Target, describeNode(r.Nodes[edge.Target], " "))
+			} else {
+				fmt.Fprintf(&buf, "%s\n", edge.Problem)
+			}
+		}
+	}
+
+	if len(r.Unevaluated) > 0 {
+		buf.WriteString("unevaluated:\n")
+		for _, unevaluated := range r.Unevaluated {
+			fmt.Fprintf(&buf, "\t%s\n", unevaluated)
+		}
+	}
+
+	if len(r.Unmatched) > 0 {
+		buf.WriteString("unmatched:\n")
+		for _, unmatched := range r.Unmatched {
+			fmt.Fprintf(&buf, "\t%s\n", unmatched)
+		}
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// WriteDot writes the report in Graphviz DOT format, drawn as the graph's
+// own WriteDot draws a live graph.
+func (r *Report) WriteDot(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("digraph inject {\n")
+	buf.WriteString("\tnode [shape=box];\n")
+
+	for _, node := range r.Nodes {
+		fmt.Fprintf(&buf, "\tp%d [label=%s];\n", node.ID, strconv.Quote(describeNode(node, "\n")))
+	}
+
+	unresolved := 0
+	for _, node := range r.Nodes {
+		for _, edge := range node.Dependencies {
+			if edge.Target >= 0 {
+				fmt.Fprintf(&buf, "\tp%d -> p%d [label=%s];\n", node.ID, edge.Target, strconv.Quote(edge.Field))
+				continue
+			}
+
+			style, color := "dashed", "red"
+			if edge.Optional {
+				style, color = "dotted", "grey"
+			}
+
+			request := edge.Type
+			if edge.Name != "" {
+				request += "\nname: " + edge.Name
+			}
+
+			fmt.Fprintf(&buf, "\tu%d [label=%s, shape=ellipse, color=%s, fontcolor=%s];\n",
+				unresolved,
+				strconv.Quote(edge.Problem+"\n"+request),
+				color,
+				color)
+			fmt.Fprintf(&buf, "\tp%d -> u%d [label=%s, style=%s, color=%s, fontcolor=%s];\n",
+				node.ID,
+				unresolved,
+				strconv.Quote(edge.Field),
+				style,
+				color,
+				color)
+			unresolved++
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func describeNode(node Node, separator string) string {
+	description := node.Kind + separator + node.Type
+	if node.Name != "" {
+		description += separator + "name: " + node.Name
+	}
+
+	if node.Context != "" {
+		description += separator + "context: " + node.Context
+	}
+
+	return description
+}
+
+func describeEdge(edge Edge) string {
+	description := edge.Type
+	if edge.Name != "" {
+		description += " named " + edge.Name
+	}
+
+	if edge.Optional {
+		description += " (optional)"
+	}
+
+	return description
+}
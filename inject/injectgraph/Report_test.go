@@ -0,0 +1,112 @@
+package injectgraph_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"github.com/impinj/go-inject/inject/injectgraph"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+)
+
+var _ = Describe("Report", func() {
+	var (
+		report *injectgraph.Report
+	)
+
+	BeforeEach(func() {
+		pkgs, err := packages.Load(&packages.Config{
+			Mode: packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		}, "./testdata/app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(packages.PrintErrors(pkgs)).To(BeZero())
+
+		dir, err := filepath.Abs("testdata/app")
+		Expect(err).NotTo(HaveOccurred())
+
+		report = injectgraph.Analyze(pkgs, dir)
+	})
+
+	It("Finds bindings given to Provide, including package-level sets", func() {
+		Expect(report.Nodes).To(HaveLen(6))
+
+		Expect(report.Nodes[0].Kind).To(Equal("ValueProvider"))
+		Expect(report.Nodes[0].Type).To(Equal("*app.Config"))
+		Expect(report.Nodes[1].Kind).To(Equal("BuilderProvider"))
+		Expect(report.Nodes[2].Kind).To(Equal("TypeProvider"))
+		Expect(report.Nodes[2].Position).To(Equal("app.go:43:4"))
+	})
+
+	It("Resolves dependencies as the graph would", func() {
+		deps := report.Nodes[2].Dependencies
+		Expect(deps).To(HaveLen(4))
+
+		Expect(deps[0].Target).To(Equal(1))
+		Expect(deps[1].Target).To(Equal(0))
+		Expect(deps[2].Target).To(Equal(-1))
+		Expect(deps[2].Problem).To(Equal("unbound (may come from a source)"))
+		Expect(deps[3].Optional).To(BeTrue())
+	})
+
+	It("Includes inject-tagged structs which no binding provides", func() {
+		Expect(report.Nodes[5].Kind).To(Equal("struct"))
+		Expect(report.Nodes[5].Type).To(Equal("app.Handler"))
+		Expect(report.Nodes[5].Dependencies[0].Target).To(Equal(2))
+	})
+
+	It("Evaluates interfaces bound to implementations", func() {
+		Expect(report.Nodes[3].Kind).To(Equal("TypeProvider"))
+		Expect(report.Nodes[3].Type).To(Equal("app.StdLogger"))
+		Expect(report.Nodes[3].Dependencies[0].Target).To(Equal(0))
+
+		Expect(report.Nodes[4].Type).To(Equal("app.Audited"))
+		Expect(report.Nodes[4].Dependencies[0].Target).To(Equal(3))
+	})
+
+	It("Replaces the bindings overrides stand in for", func() {
+		Expect(report.Nodes[0].Kind).To(Equal("ValueProvider"))
+		Expect(report.Nodes[0].Type).To(Equal("*app.Config"))
+		Expect(report.Nodes[0].Position).To(Equal("app.go:69:14"))
+		Expect(report.Nodes[2].Dependencies[1].Target).To(Equal(0))
+
+		Expect(report.Unmatched).To(ConsistOf(
+			"app.go:70:14: Override replaces no binding.",
+			"app.go:71:14: Override replaces no binding.",
+		))
+	})
+
+	It("Keeps bindings of other types which an override also satisfies", func() {
+		Expect(report.Nodes[1].Kind).To(Equal("BuilderProvider"))
+		Expect(report.Nodes[1].Type).To(Equal("app.Greeter"))
+	})
+
+	It("Lists providers which cannot be evaluated", func() {
+		Expect(report.Unevaluated).To(ConsistOf("app.go:44:3: Cannot evaluate provider statically; use a composite literal."))
+	})
+
+	It("Writes a text report", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteText(&buf)).To(Succeed())
+
+		Expect(buf.String()).To(ContainSubstring("[2] TypeProvider *app.Server\n" +
+			"\tat app.go:43:4\n" +
+			"\tServer.Greeter app.Greeter -> [1] BuilderProvider app.Greeter\n" +
+			"\tServer.Config *app.Config -> [0] ValueProvider *app.Config\n" +
+			"\tServer.Port int named port -> unbound (may come from a source)\n" +
+			"\tServer.Audit app.Greeter named audit (optional) -> unbound (may come from a source)\n"))
+		Expect(buf.String()).To(HaveSuffix("unevaluated:\n\tapp.go:44:3: Cannot evaluate provider statically; use a composite literal.\n" +
+			"unmatched:\n\tapp.go:70:14: Override replaces no binding.\n\tapp.go:71:14: Override replaces no binding.\n"))
+	})
+
+	It("Writes a DOT graph", func() {
+		var buf bytes.Buffer
+		Expect(report.WriteDot(&buf)).To(Succeed())
+
+		Expect(buf.String()).To(HavePrefix("digraph inject {\n"))
+		Expect(buf.String()).To(ContainSubstring(`p5 [label="struct\napp.Handler"];`))
+		Expect(buf.String()).To(ContainSubstring(`p5 -> p2 [label="Handler.Server"];`))
+		Expect(buf.String()).To(ContainSubstring(`p2 -> u0 [label="Server.Port", style=dashed, color=red, fontcolor=red];`))
+		Expect(buf.String()).To(ContainSubstring(`p2 -> u1 [label="Server.Audit", style=dotted, color=grey, fontcolor=grey];`))
+	})
+})
@@ -0,0 +1,13 @@
+package injectgraph_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestInjectgraph(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Injectgraph Suite")
+}
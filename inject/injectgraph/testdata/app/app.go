@@ -0,0 +1,72 @@
+package app
+
+import (
+	"reflect"
+
+	"github.com/impinj/go-inject/inject"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct{}
+
+func (English) Greet() string {
+	return "hello"
+}
+
+type Config struct {
+	URL string
+}
+
+type Server struct {
+	Greeter Greeter `inject:""`
+	Config  *Config `inject:""`
+	Port    int     `inject:"port"`
+	Audit   Greeter `inject:"audit,optional"`
+}
+
+type Handler struct {
+	Server *Server `inject:""`
+}
+
+var base = []inject.Provider{
+	&inject.ValueProvider{Value: &Config{}},
+}
+
+func Wire(source func() inject.Provider) inject.Graph {
+	g := inject.NewGraph()
+	g.Provide(base...)
+	g.Provide(
+		&inject.BuilderProvider{Builder: func() Greeter { return English{} }},
+		&inject.TypeProvider{Type: reflect.TypeOf(&Server{}), Scope: inject.Singleton, ResolveContext: g},
+		source(),
+	)
+
+	return g
+}
+
+type Logger interface {
+	Log(message string)
+}
+
+type StdLogger struct {
+	Config *Config `inject:""`
+}
+
+func (StdLogger) Log(message string) {}
+
+type Audited struct {
+	Logger Logger `inject:""`
+}
+
+func WireLogging(g inject.Graph) {
+	g.Provide(inject.Bind(g, reflect.TypeOf((*Logger)(nil)).Elem(), reflect.TypeOf(StdLogger{})))
+}
+
+func Stub(g inject.Graph) {
+	g.Override(&inject.ValueProvider{Value: &Config{URL: "http://localhost"}})
+	g.Override(&inject.ValueProvider{Name: "missing", Value: 1})
+	g.Override(&inject.ValueProvider{Value: English{}})
+}
@@ -0,0 +1,102 @@
+// Package injectlint reports inject tags which the graph would reject late
+// or silently ignore.
+package injectlint
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/impinj/go-inject/inject/internal/wiring"
+	"golang.org/x/tools/go/analysis"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "injectlint",
+	Doc: `check inject struct tags
+
+Reports inject-tagged fields which the graph cannot set because they are
+unexported, inject tags which the graph ignores because of their syntax or
+position in the struct tag, unknown tag options, and struct values whose
+own inject-tagged fields are never completed because they are copied.`,
+	Run: run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if st, ok := node.(*ast.StructType); ok {
+				for _, field := range st.Fields.List {
+					checkField(pass, field)
+				}
+			}
+
+			return true
+		})
+	}
+
+	return nil, nil
+}
+
+func checkField(pass *analysis.Pass, field *ast.Field) {
+	if field.Tag == nil {
+		return
+	}
+
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return
+	}
+
+	value, ok := wiring.LookupTag(tag)
+	if !ok {
+		checkIgnored(pass, field, tag)
+		return
+	}
+
+	parts := strings.Split(value, ",")
+	for _, option := range parts[1:] {
+		switch strings.TrimSpace(option) {
+		case "optional", "secret":
+
+		default:
+			pass.Reportf(field.Tag.Pos(), "unknown inject tag option %q", strings.TrimSpace(option))
+		}
+	}
+
+	for _, name := range field.Names {
+		if !name.IsExported() {
+			pass.Reportf(name.Pos(), "inject-tagged field %s is unexported; the graph cannot set it", name.Name)
+		}
+	}
+
+	typ := pass.TypesInfo.TypeOf(field.Type)
+	if _, ok := typ.Underlying().(*types.Struct); ok && len(wiring.Fields(typ)) > 0 {
+		pass.Reportf(field.Type.Pos(), "inject-tagged field holds %s by value; its inject-tagged fields are never completed, use a pointer",
+			types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+	}
+}
+
+// checkIgnored reports tags which mention inject but which the graph does
+// not recognise, as it only considers the first key:"value" pair.
+func checkIgnored(pass *analysis.Pass, field *ast.Field, tag string) {
+	if _, ok := reflect.StructTag(tag).Lookup("inject"); ok {
+		if strings.Contains(tagValue(tag), " ") {
+			pass.Reportf(field.Tag.Pos(), "inject tag contains a space; the graph splits tags on spaces and ignores it")
+		} else {
+			pass.Reportf(field.Tag.Pos(), "inject tag is not the first key in the struct tag; the graph ignores it")
+		}
+		return
+	}
+
+	if strings.Contains(tag, "inject:") {
+		pass.Reportf(field.Tag.Pos(), `malformed inject tag; the graph ignores it (want inject:"name,optional")`)
+	}
+}
+
+func tagValue(tag string) string {
+	value, _ := reflect.StructTag(tag).Lookup("inject")
+	return value
+}
@@ -0,0 +1,14 @@
+package injectlint_test
+
+import (
+	. "github.com/onsi/ginkgo"
+
+	"github.com/impinj/go-inject/inject/injectlint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+var _ = Describe("Analyzer", func() {
+	It("Reports misused inject tags", func() {
+		analysistest.Run(GinkgoT(), analysistest.TestData(), injectlint.Analyzer, "a")
+	})
+})
@@ -0,0 +1,13 @@
+package injectlint_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestInjectlint(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Injectlint Suite")
+}
@@ -0,0 +1,32 @@
+package a
+
+type Greeter interface {
+	Greet() string
+}
+
+type Config struct {
+	URL string
+}
+
+type Client struct {
+	Greeter Greeter `inject:""`
+}
+
+type Service struct {
+	Greeter  Greeter `inject:""`
+	Named    Greeter `inject:"greeter,optional"`
+	Password string  `inject:"password,secret"`
+	Config   Config  `inject:""`
+	Client   *Client `inject:""`
+	Untagged Client
+
+	greeter Greeter `inject:""` // want `inject-tagged field greeter is unexported; the graph cannot set it`
+
+	Copied Client `inject:""` // want `inject-tagged field holds Client by value; its inject-tagged fields are never completed, use a pointer`
+
+	Second  Greeter `json:"second" inject:""`   // want `inject tag is not the first key in the struct tag; the graph ignores it`
+	Spaced  Greeter `inject:"spaced, optional"` // want `inject tag contains a space; the graph splits tags on spaces and ignores it`
+	Bare    Greeter `inject:greeter`            // want `malformed inject tag; the graph ignores it`
+	Typo    Greeter `inject:",optinal"`         // want `unknown inject tag option "optinal"`
+	Ignored Greeter `json:"ignored"`
+}
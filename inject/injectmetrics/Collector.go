@@ -0,0 +1,64 @@
+package injectmetrics
+
+import (
+	"github.com/impinj/go-inject/inject"
+)
+
+// Collector is a Listener which records the graph's lookups, cache hits,
+// builder invocations, failures and construction latencies.
+//
+// A lookup is a cache hit when the provider it chooses was already
+// complete, such as a singleton which has been constructed. Failures are
+// labelled by kind: "ambiguous" when several providers matched, "missing"
+// when none did, and "field" when a field could not be set.
+type Collector struct {
+	Recorder Recorder
+}
+
+// NewCollector creates a collector which records metrics with recorder.
+func NewCollector(recorder Recorder) *Collector {
+	return &Collector{Recorder: recorder}
+}
+
+func (c *Collector) OnEvent(event inject.Event) {
+	switch event.Kind {
+	case inject.LookupStarted:
+		c.Recorder.Count("inject_lookups_total", "Lookups made by the graph.", nil)
+
+	case inject.ProviderChosen:
+		if event.Provider.IsComplete() {
+			c.Recorder.Count("inject_cache_hits_total", "Lookups answered by an already complete provider.", nil)
+		}
+
+	case inject.BuilderInvoked:
+		labels := map[string]string{"type": typeLabel(event)}
+		c.Recorder.Count("inject_builder_invocations_total", "Builders invoked by the graph.", labels)
+		c.Recorder.Observe("inject_construction_seconds", "Time taken by builders, including their parameters.",
+			labels,
+			event.Duration.Seconds())
+
+	case inject.ResolutionFailed:
+		c.Recorder.Count("inject_failures_total", "Lookups and fields which failed.",
+			map[string]string{"kind": failureKind(event)})
+	}
+}
+
+func failureKind(event inject.Event) string {
+	switch {
+	case event.Owner != nil:
+		return "field"
+
+	case len(event.Candidates) > 1:
+		return "ambiguous"
+	}
+
+	return "missing"
+}
+
+func typeLabel(event inject.Event) string {
+	if event.Type == nil {
+		return ""
+	}
+
+	return event.Type.String()
+}
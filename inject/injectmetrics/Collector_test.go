@@ -0,0 +1,84 @@
+package injectmetrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"github.com/impinj/go-inject/inject"
+	"github.com/impinj/go-inject/inject/injectmetrics"
+	"reflect"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct{}
+
+func (English) Greet() string { return "hello" }
+
+type Service struct {
+	Greeter Greeter `inject:""`
+}
+
+type Other struct {
+	Greeter Greeter `inject:"missing,optional"`
+}
+
+var _ = Describe("Collector", func() {
+	var (
+		r *injectmetrics.Registry
+		g inject.Graph
+	)
+
+	BeforeEach(func() {
+		r = injectmetrics.NewRegistry()
+		g = inject.NewGraph(inject.WithListeners(injectmetrics.NewCollector(r)))
+	})
+
+	exposition := func() string {
+		var buf bytes.Buffer
+		_, err := r.WriteTo(&buf)
+		Expect(err).NotTo(HaveOccurred())
+		return buf.String()
+	}
+
+	It("Counts lookups, builders and cache hits", func() {
+		g.Provide(
+			&inject.ValueProvider{Value: &Service{}},
+			&inject.SingletonProvider{Provider: &inject.BuilderProvider{
+				Builder: func() Greeter { return English{} },
+			}})
+		Expect(g.Resolve()).To(Succeed())
+
+		greeterType := reflect.TypeOf((*Greeter)(nil)).Elem()
+		_, err := g.Find(greeterType, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		out := exposition()
+		Expect(out).To(ContainSubstring("inject_lookups_total 2\n"))
+		Expect(out).To(ContainSubstring("inject_cache_hits_total 1\n"))
+		Expect(out).To(ContainSubstring(`inject_builder_invocations_total{type="injectmetrics_test.Greeter"} 1` + "\n"))
+		Expect(out).To(ContainSubstring(`inject_construction_seconds_count{type="injectmetrics_test.Greeter"} 1` + "\n"))
+		Expect(out).To(ContainSubstring("# TYPE inject_construction_seconds histogram\n"))
+	})
+
+	It("Counts failures by kind", func() {
+		g.Provide(
+			&inject.ValueProvider{Value: &Service{}},
+			&inject.ValueProvider{Value: &Other{}})
+		Expect(g.Resolve()).NotTo(Succeed())
+
+		g.Provide(
+			&inject.ValueProvider{Value: English{}},
+			&inject.ValueProvider{Value: English{}})
+		_, err := g.Find(reflect.TypeOf((*Greeter)(nil)).Elem(), nil, "")
+		Expect(err).To(HaveOccurred())
+
+		out := exposition()
+		// Optional fields which are left unset still count as failed lookups.
+		Expect(out).To(ContainSubstring(`inject_failures_total{kind="missing"} 2` + "\n"))
+		Expect(out).To(ContainSubstring(`inject_failures_total{kind="ambiguous"} 1` + "\n"))
+	})
+})
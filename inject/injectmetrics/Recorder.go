@@ -0,0 +1,13 @@
+// Package injectmetrics collects metrics about what a graph does.
+package injectmetrics
+
+// Recorder receives metrics from a Collector. Registry implements it and
+// exposes the metrics in the Prometheus text format; other implementations
+// can forward them to a metrics client.
+type Recorder interface {
+	// Count increments a counter.
+	Count(name, help string, labels map[string]string)
+
+	// Observe adds a value to a histogram.
+	Observe(name, help string, labels map[string]string, value float64)
+}
@@ -0,0 +1,194 @@
+package injectmetrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the histogram buckets
+// used by a Registry.
+var DefaultBuckets = []float64{.0005, .001, .005, .01, .05, .1, .5, 1, 5}
+
+// Registry is an in-memory Recorder which writes its metrics in the
+// Prometheus text exposition format. It serves them over HTTP as well, so
+// it can be mounted directly as a scrape endpoint. Buckets must not change
+// once values have been observed.
+type Registry struct {
+	Buckets []float64
+
+	mutex   sync.Mutex
+	metrics map[string]*metric
+}
+
+type metric struct {
+	help   string
+	kind   string
+	series map[string]*series
+}
+
+type series struct {
+	labels  string
+	value   float64
+	buckets []uint64
+	sum     float64
+}
+
+// NewRegistry creates an empty registry using DefaultBuckets.
+func NewRegistry() *Registry {
+	return &Registry{Buckets: DefaultBuckets}
+}
+
+func (r *Registry) Count(name, help string, labels map[string]string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.series(name, help, "counter", labels).value++
+}
+
+func (r *Registry) Observe(name, help string, labels map[string]string, value float64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	s := r.series(name, help, "histogram", labels)
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(r.Buckets))
+	}
+
+	for i, bound := range r.Buckets {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.value++
+	s.sum += value
+}
+
+func (r *Registry) series(name, help, kind string, labels map[string]string) *series {
+	if r.metrics == nil {
+		r.metrics = map[string]*metric{}
+	}
+
+	m, ok := r.metrics[name]
+	if !ok {
+		m = &metric{help: help, kind: kind, series: map[string]*series{}}
+		r.metrics[name] = m
+	}
+
+	key := formatLabels(labels)
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labels: key}
+		m.series[key] = s
+	}
+
+	return s
+}
+
+// WriteTo writes every metric, sorted by name and labels.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var buf bytes.Buffer
+	for _, name := range sortedKeys(r.metrics) {
+		m := r.metrics[name]
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, escape(m.help, false))
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, m.kind)
+
+		for _, key := range sortedKeys(m.series) {
+			s := m.series[key]
+			if m.kind != "histogram" {
+				fmt.Fprintf(&buf, "%s%s %s\n", name, braces(s.labels), formatFloat(s.value))
+				continue
+			}
+
+			for i, bound := range r.Buckets {
+				fmt.Fprintf(&buf, "%s_bucket%s %d\n", name, braces(join(s.labels, `le="`+formatFloat(bound)+`"`)), s.buckets[i])
+			}
+			fmt.Fprintf(&buf, "%s_bucket%s %s\n", name, braces(join(s.labels, `le="+Inf"`)), formatFloat(s.value))
+			fmt.Fprintf(&buf, "%s_sum%s %s\n", name, braces(s.labels), formatFloat(s.sum))
+			fmt.Fprintf(&buf, "%s_count%s %s\n", name, braces(s.labels), formatFloat(s.value))
+		}
+	}
+
+	return buf.WriteTo(w)
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+// formatLabels formats labels as name="value" pairs sorted by name.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, name := range sortedKeys(labels) {
+		pairs = append(pairs, name+`="`+escape(labels[name], true)+`"`)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func escape(s string, quoted bool) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	if quoted {
+		s = strings.Replace(s, `"`, `\"`, -1)
+	}
+
+	return s
+}
+
+func join(labels, label string) string {
+	if labels == "" {
+		return label
+	}
+
+	return labels + "," + label
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+
+	return "{" + labels + "}"
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*metric:
+		for key := range m {
+			keys = append(keys, key)
+		}
+
+	case map[string]*series:
+		for key := range m {
+			keys = append(keys, key)
+		}
+
+	case map[string]string:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,78 @@
+package injectmetrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"github.com/impinj/go-inject/inject/injectmetrics"
+	"io/ioutil"
+	"net/http/httptest"
+)
+
+var _ = Describe("Registry", func() {
+	var (
+		r *injectmetrics.Registry
+	)
+
+	BeforeEach(func() {
+		r = injectmetrics.NewRegistry()
+		r.Buckets = []float64{0.1, 1}
+	})
+
+	exposition := func() string {
+		var buf bytes.Buffer
+		_, err := r.WriteTo(&buf)
+		Expect(err).NotTo(HaveOccurred())
+		return buf.String()
+	}
+
+	It("Writes counters", func() {
+		r.Count("requests_total", "Requests.", nil)
+		r.Count("requests_total", "Requests.", nil)
+		r.Count("errors_total", "Errors.", map[string]string{"kind": "b"})
+		r.Count("errors_total", "Errors.", map[string]string{"kind": "a"})
+
+		Expect(exposition()).To(Equal(`# HELP errors_total Errors.
+# TYPE errors_total counter
+errors_total{kind="a"} 1
+errors_total{kind="b"} 1
+# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total 2
+`))
+	})
+
+	It("Writes histograms", func() {
+		labels := map[string]string{"type": "*pkg.T"}
+		r.Observe("latency_seconds", "Latency.", labels, 0.05)
+		r.Observe("latency_seconds", "Latency.", labels, 0.5)
+		r.Observe("latency_seconds", "Latency.", labels, 2)
+
+		Expect(exposition()).To(Equal(`# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{type="*pkg.T",le="0.1"} 1
+latency_seconds_bucket{type="*pkg.T",le="1"} 2
+latency_seconds_bucket{type="*pkg.T",le="+Inf"} 3
+latency_seconds_sum{type="*pkg.T"} 2.55
+latency_seconds_count{type="*pkg.T"} 3
+`))
+	})
+
+	It("Escapes label values", func() {
+		r.Count("total", "Total.", map[string]string{"name": "a\"b\\c\nd"})
+
+		Expect(exposition()).To(ContainSubstring(`total{name="a\"b\\c\nd"} 1`))
+	})
+
+	It("Serves the exposition over HTTP", func() {
+		r.Count("total", "Total.", nil)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+		Expect(rec.Header().Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4"))
+		body, _ := ioutil.ReadAll(rec.Body)
+		Expect(string(body)).To(ContainSubstring("total 1\n"))
+	})
+})
@@ -0,0 +1,13 @@
+package injectmetrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestInjectmetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Injectmetrics Suite")
+}
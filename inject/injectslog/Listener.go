@@ -0,0 +1,90 @@
+//go:build go1.21
+// +build go1.21
+
+// Package injectslog logs what a graph does through log/slog.
+package injectslog
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+
+	"github.com/impinj/go-inject/inject"
+)
+
+// Listener logs graph events to Logger at Level, which defaults to
+// slog.LevelDebug, so that resolution is silent unless the logger's handler
+// is configured for debugging. Every record's message is the event kind;
+// its attributes are whichever of type, name, context, provider, field,
+// candidates, duration and error the event sets.
+type Listener struct {
+	Logger *slog.Logger
+	Level  slog.Level
+}
+
+// New returns a listener logging to logger at debug level.
+func New(logger *slog.Logger) *Listener {
+	return &Listener{Logger: logger, Level: slog.LevelDebug}
+}
+
+func (l *Listener) OnEvent(event inject.Event) {
+	logger := l.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	ctx := context.Background()
+	if !logger.Enabled(ctx, l.Level) {
+		return
+	}
+
+	logger.LogAttrs(ctx, l.Level, event.Kind.String(), Attrs(event)...)
+}
+
+// Attrs returns the attributes describing an event.
+func Attrs(event inject.Event) []slog.Attr {
+	var attrs []slog.Attr
+	if event.Type != nil {
+		attrs = append(attrs, slog.String("type", event.Type.String()))
+	}
+
+	if event.Name != "" {
+		attrs = append(attrs, slog.String("name", event.Name))
+	}
+
+	if event.Context != nil {
+		attrs = append(attrs, slog.String("context", event.Context.String()))
+	}
+
+	if event.Provider != nil {
+		attrs = append(attrs, slog.String("provider", kind(event.Provider)))
+	}
+
+	if event.Owner != nil {
+		attrs = append(attrs, slog.String("field", event.Owner.String()+"."+event.Field))
+	}
+
+	switch event.Kind {
+	case inject.CandidatesByType, inject.CandidatesByContext, inject.CandidatesByName:
+		attrs = append(attrs, slog.Int("candidates", len(event.Candidates)))
+
+	case inject.BuilderInvoked, inject.Completed:
+		attrs = append(attrs, slog.Duration("duration", event.Duration))
+	}
+
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+
+	return attrs
+}
+
+// kind names the kind of a provider, e.g. BuilderProvider.
+func kind(provider inject.Provider) string {
+	typeInfo := reflect.TypeOf(provider)
+	for typeInfo.Kind() == reflect.Ptr {
+		typeInfo = typeInfo.Elem()
+	}
+
+	return typeInfo.Name()
+}
@@ -0,0 +1,104 @@
+//go:build go1.21
+// +build go1.21
+
+package injectslog_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"encoding/json"
+	"github.com/impinj/go-inject/inject"
+	"github.com/impinj/go-inject/inject/injectslog"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct{}
+
+func (English) Greet() string { return "hello" }
+
+type Service struct {
+	Greeter Greeter `inject:"greeter"`
+}
+
+var _ = Describe("Listener", func() {
+	var (
+		buf bytes.Buffer
+	)
+
+	records := func() []map[string]interface{} {
+		var retVal []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+
+			record := map[string]interface{}{}
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			retVal = append(retVal, record)
+		}
+		return retVal
+	}
+
+	resolve := func(level slog.Level) {
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: level}))
+
+		g := inject.NewGraph(inject.WithListeners(injectslog.New(logger)))
+		g.Provide(
+			&inject.ValueProvider{Value: &Service{}},
+			&inject.BuilderProvider{Name: "greeter", Builder: func() Greeter { return English{} }})
+		Expect(g.Resolve()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		buf.Reset()
+	})
+
+	It("Is silent unless debugging", func() {
+		resolve(slog.LevelInfo)
+
+		Expect(buf.String()).To(BeEmpty())
+	})
+
+	It("Logs events with consistent attributes when debugging", func() {
+		resolve(slog.LevelDebug)
+
+		var set map[string]interface{}
+		for _, record := range records() {
+			Expect(record["level"]).To(Equal("DEBUG"))
+			if record["msg"] == "FieldSet" {
+				set = record
+			}
+		}
+
+		Expect(set).NotTo(BeNil())
+		Expect(set["type"]).To(Equal("injectslog_test.Greeter"))
+		Expect(set["name"]).To(Equal("greeter"))
+		Expect(set["context"]).To(Equal("injectslog_test.Service"))
+		Expect(set["provider"]).To(Equal("BuilderProvider"))
+		Expect(set["field"]).To(Equal("injectslog_test.Service.Greeter"))
+	})
+
+	It("Describes events as attributes", func() {
+		attrs := injectslog.Attrs(inject.Event{
+			Kind:     inject.BuilderInvoked,
+			Type:     reflect.TypeOf(English{}),
+			Provider: &inject.BuilderProvider{},
+			Duration: time.Second,
+		})
+
+		Expect(attrs).To(Equal([]slog.Attr{
+			slog.String("type", "injectslog_test.English"),
+			slog.String("provider", "BuilderProvider"),
+			slog.Duration("duration", time.Second),
+		}))
+	})
+})
@@ -0,0 +1,142 @@
+// Package injecttest completes structs under test, supplying mocks for the
+// interfaces they depend on which no real provider binds.
+package injecttest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	"github.com/impinj/go-inject/inject"
+)
+
+var controllerType = reflect.TypeOf((*gomock.Controller)(nil))
+
+// Harness wraps a graph of real providers. When completing a struct, every
+// inject-tagged interface field which the graph cannot satisfy is given a
+// mock created by one of the registered mock constructors, and the mock is
+// provided to the graph so that everything depending on the interface
+// shares it.
+type Harness struct {
+	Graph      inject.Graph
+	Controller *gomock.Controller
+
+	constructors []reflect.Value
+	mocks        map[reflect.Type]interface{}
+}
+
+// New creates a harness whose graph holds the given real providers.
+func New(ctrl *gomock.Controller, providers ...inject.Provider) *Harness {
+	g := inject.NewGraph()
+	g.Provide(providers...)
+
+	return &Harness{
+		Graph:      g,
+		Controller: ctrl,
+		mocks:      map[reflect.Type]interface{}{},
+	}
+}
+
+// Mocks registers constructors generated by mockgen, such as
+// mock_inject.NewMockProvider, each taking a *gomock.Controller and
+// returning a mock.
+func (h *Harness) Mocks(constructors ...interface{}) *Harness {
+	for _, constructor := range constructors {
+		h.constructors = append(h.constructors, reflect.ValueOf(constructor))
+	}
+
+	return h
+}
+
+// Complete completes v, a pointer to a struct, mocking any interface it or
+// its dependencies need which the graph does not bind.
+func (h *Harness) Complete(v interface{}) error {
+	if err := h.validateConstructors(); err != nil {
+		return err
+	}
+
+	// v is walked in a graph of its own so that it is not bound in h.Graph,
+	// where it would be ambiguous with the next instance of its type.
+	subject := inject.NewGraph()
+	subject.Provide(&inject.ValueProvider{Value: v})
+	points := append(subject.InjectionPoints(), h.Graph.InjectionPoints()...)
+
+	var missing []string
+	for _, point := range points {
+		if point.Type.Kind() != reflect.Interface ||
+			h.Graph.Provides(point.Type, point.Owner, point.Name) {
+			continue
+		}
+
+		mock, err := h.mock(point.Type)
+		if err != nil {
+			return err
+		}
+
+		if mock == nil {
+			missing = append(missing, point.Type.String())
+			continue
+		}
+
+		h.Graph.Provide(&inject.ValueProvider{
+			Name:  point.Name,
+			Value: mock,
+			As:    []reflect.Type{point.Type},
+		})
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("No mock registered for %s; generate one with mockgen and pass its constructor to Mocks.",
+			strings.Join(missing, ", "))
+	}
+
+	return h.Graph.Complete(v)
+}
+
+// Mock returns the mock created for an interface, or nil if none was.
+func (h *Harness) Mock(iface reflect.Type) interface{} {
+	return h.mocks[iface]
+}
+
+// mock returns the mock for an interface, creating it if needed, or nil if
+// no registered constructor creates a suitable mock.
+func (h *Harness) mock(iface reflect.Type) (interface{}, error) {
+	if mock, ok := h.mocks[iface]; ok {
+		return mock, nil
+	}
+
+	var candidates []reflect.Value
+	for _, constructor := range h.constructors {
+		if constructor.Type().Out(0).Implements(iface) {
+			candidates = append(candidates, constructor)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+
+	case 1:
+		mock := candidates[0].Call([]reflect.Value{reflect.ValueOf(h.Controller)})[0].Interface()
+		h.mocks[iface] = mock
+		return mock, nil
+
+	default:
+		return nil, fmt.Errorf("Found multiple mock constructors for %s.", iface)
+	}
+}
+
+func (h *Harness) validateConstructors() error {
+	for _, constructor := range h.constructors {
+		typeInfo := constructor.Type()
+		if typeInfo.Kind() != reflect.Func ||
+			typeInfo.NumIn() != 1 ||
+			typeInfo.In(0) != controllerType ||
+			typeInfo.NumOut() != 1 {
+			return fmt.Errorf("Mock constructors must be of the form func(*gomock.Controller) T, got %s.", typeInfo)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package injecttest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"github.com/impinj/go-inject/inject"
+	"github.com/impinj/go-inject/inject/injecttest"
+	"github.com/impinj/go-inject/inject/mock"
+	"reflect"
+)
+
+type Service struct {
+	Provider inject.Provider `inject:""`
+	Graph    inject.Graph    `inject:"graph"`
+	Label    string          `inject:"label"`
+}
+
+type Client struct {
+	Service  *Service        `inject:""`
+	Provider inject.Provider `inject:""`
+}
+
+var providerType = reflect.TypeOf((*inject.Provider)(nil)).Elem()
+var graphType = reflect.TypeOf((*inject.Graph)(nil)).Elem()
+
+var _ = Describe("Harness", func() {
+	var (
+		ctrl *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("Mocks unbound interfaces and keeps real providers", func() {
+		h := injecttest.New(ctrl, &inject.ValueProvider{Name: "label", Value: "real"}).
+			Mocks(mock_inject.NewMockProvider, mock_inject.NewMockGraph)
+
+		s := &Service{}
+		Expect(h.Complete(s)).To(Succeed())
+
+		Expect(s.Label).To(Equal("real"))
+		Expect(s.Provider).To(BeIdenticalTo(h.Mock(providerType)))
+		Expect(s.Graph).To(BeIdenticalTo(h.Mock(graphType)))
+		Expect(s.Provider).To(BeAssignableToTypeOf(&mock_inject.MockProvider{}))
+	})
+
+	It("Shares a mock between dependents", func() {
+		h := injecttest.New(ctrl,
+			&inject.ValueProvider{Name: "label", Value: "real"},
+			&inject.ValueProvider{Value: &Service{}}).
+			Mocks(mock_inject.NewMockProvider, mock_inject.NewMockGraph)
+
+		c := &Client{}
+		Expect(h.Complete(c)).To(Succeed())
+
+		Expect(c.Service).NotTo(BeNil())
+		Expect(c.Provider).To(BeIdenticalTo(c.Service.Provider))
+
+		h.Mock(providerType).(*mock_inject.MockProvider).EXPECT().IsComplete().Return(true)
+		Expect(c.Service.Provider.IsComplete()).To(BeTrue())
+	})
+
+	It("Does not mock interfaces which are bound", func() {
+		real := mock_inject.NewMockProvider(ctrl)
+		h := injecttest.New(ctrl,
+			&inject.ValueProvider{Name: "label", Value: "real"},
+			&inject.ValueProvider{Value: real, As: []reflect.Type{providerType}}).
+			Mocks(mock_inject.NewMockProvider, mock_inject.NewMockGraph)
+
+		s := &Service{}
+		Expect(h.Complete(s)).To(Succeed())
+
+		Expect(s.Provider).To(BeIdenticalTo(real))
+		Expect(h.Mock(providerType)).To(BeNil())
+	})
+
+	It("Completes several instances of the same type", func() {
+		h := injecttest.New(ctrl, &inject.ValueProvider{Name: "label", Value: "real"}).
+			Mocks(mock_inject.NewMockProvider, mock_inject.NewMockGraph)
+
+		first, second := &Service{}, &Service{}
+		Expect(h.Complete(first)).To(Succeed())
+		Expect(h.Complete(second)).To(Succeed())
+
+		Expect(second.Label).To(Equal("real"))
+		Expect(second.Provider).To(BeIdenticalTo(first.Provider))
+		Expect(h.Graph.Provides(reflect.TypeOf(first), nil, "")).To(BeFalse())
+	})
+
+	It("Reports interfaces without a registered mock", func() {
+		h := injecttest.New(ctrl, &inject.ValueProvider{Name: "label", Value: "real"}).
+			Mocks(mock_inject.NewMockProvider)
+
+		err := h.Complete(&Service{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("inject.Graph"))
+		Expect(err.Error()).To(ContainSubstring("mockgen"))
+	})
+
+	It("Rejects ambiguous mock constructors", func() {
+		h := injecttest.New(ctrl, &inject.ValueProvider{Name: "label", Value: "real"}).
+			Mocks(mock_inject.NewMockProvider, mock_inject.NewMockProvider, mock_inject.NewMockGraph)
+
+		Expect(h.Complete(&Service{})).NotTo(Succeed())
+	})
+
+	It("Rejects constructors of the wrong form", func() {
+		h := injecttest.New(ctrl).Mocks(func() *mock_inject.MockProvider { return nil })
+
+		Expect(h.Complete(&Service{})).NotTo(Succeed())
+	})
+})
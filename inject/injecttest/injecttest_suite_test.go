@@ -0,0 +1,13 @@
+package injecttest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestInjecttest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Injecttest Suite")
+}
@@ -0,0 +1,319 @@
+// Package wiring describes, from type-checked source, the providers given to
+// a graph and the inject-tagged fields they satisfy, selecting providers by
+// the same rules the graph applies at runtime.
+package wiring
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+)
+
+// InjectPath is the import path of the inject package.
+const InjectPath = "github.com/impinj/go-inject/inject"
+
+// Binding is a provider whose fields could be evaluated statically.
+type Binding struct {
+	// Kind is the provider's type name, e.g. BuilderProvider.
+	Kind string
+	Pos  token.Pos
+
+	// Type is the type provided: the builder's result, the type of a value
+	// once any conversions to interfaces are removed, or a TypeProvider's
+	// Type.
+	Type    types.Type
+	Name    string
+	Context types.Type
+	As      []types.Type
+
+	// Singleton is set for values, singleton-scoped TypeProviders and
+	// providers wrapped in a SingletonProvider.
+	Singleton bool
+
+	// Value is the expression given as a ValueProvider's Value, without any
+	// conversions to interfaces, and Builder the expression given as a
+	// BuilderProvider's Builder.
+	Value   ast.Expr
+	Builder ast.Expr
+
+	// Signature is the builder's signature.
+	Signature *types.Signature
+}
+
+// ParseProvider evaluates an expression constructing one of the inject
+// package's providers, such as &inject.BuilderProvider{Builder: NewFoo} or
+// inject.Bind(g, iface, impl).
+func ParseProvider(info *types.Info, expr ast.Expr) (*Binding, error) {
+	expr = ast.Unparen(expr)
+	if call, ok := expr.(*ast.CallExpr); ok && isFunc(info, call.Fun, InjectPath, "Bind") && len(call.Args) == 3 {
+		return parseBind(info, call)
+	}
+
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = ast.Unparen(unary.X)
+	}
+
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, fmt.Errorf("Cannot evaluate provider statically; use a composite literal.")
+	}
+
+	kind := injectTypeName(info.TypeOf(lit))
+	binding := &Binding{Kind: kind, Pos: lit.Pos()}
+
+	fields := map[string]ast.Expr{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, fmt.Errorf("Cannot evaluate %s statically; use keyed fields.", kind)
+		}
+
+		if key, ok := kv.Key.(*ast.Ident); ok {
+			fields[key.Name] = kv.Value
+		}
+	}
+
+	var err error
+	for _, key := range []string{"Name", "Context", "As"} {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Name":
+			binding.Name, err = stringValue(info, value)
+
+		case "Context":
+			binding.Context, err = ReflectType(info, value)
+
+		case "As":
+			binding.As, err = reflectTypes(info, value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Cannot evaluate %s.%s statically: %s", kind, key, err)
+		}
+	}
+
+	switch kind {
+	case "ValueProvider":
+		binding.Value = fields["Value"]
+		binding.Singleton = true
+		if binding.Value == nil {
+			return nil, fmt.Errorf("ValueProvider has no Value.")
+		}
+
+		// The graph binds values by their dynamic type, so conversions to
+		// interfaces are looked through, and values whose dynamic type is
+		// unknown cannot be evaluated.
+		binding.Value = unconvert(info, binding.Value)
+		binding.Type = info.TypeOf(binding.Value)
+		if types.IsInterface(binding.Type) {
+			return nil, fmt.Errorf("Cannot evaluate ValueProvider.Value statically: its dynamic type is unknown.")
+		}
+
+	case "BuilderProvider":
+		binding.Builder = fields["Builder"]
+		if binding.Builder == nil {
+			return nil, fmt.Errorf("BuilderProvider has no Builder.")
+		}
+
+		signature, ok := info.TypeOf(binding.Builder).Underlying().(*types.Signature)
+		if !ok || signature.Results().Len() == 0 {
+			return nil, fmt.Errorf("BuilderProvider's Builder must be a function with a result.")
+		}
+		binding.Type = signature.Results().At(0).Type()
+		binding.Signature = signature
+
+		if IsResultObject(binding.Type) {
+			return nil, fmt.Errorf("Cannot evaluate result objects statically.")
+		}
+
+	case "TypeProvider":
+		if binding.Type, err = ReflectType(info, fields["Type"]); err != nil {
+			return nil, fmt.Errorf("Cannot evaluate TypeProvider.Type statically: %s", err)
+		}
+
+		if scope, ok := fields["Scope"]; ok {
+			value := info.Types[scope].Value
+			if value == nil || value.Kind() != constant.Int {
+				return nil, fmt.Errorf("Cannot evaluate TypeProvider.Scope statically.")
+			}
+
+			singleton, ok := injectObject(info.TypeOf(lit), "Singleton").(*types.Const)
+			if !ok {
+				return nil, fmt.Errorf("Cannot find the Singleton scope.")
+			}
+			binding.Singleton = constant.Compare(value, token.EQL, singleton.Val())
+		}
+
+	case "SingletonProvider":
+		inner, ok := fields["Provider"]
+		if !ok {
+			return nil, fmt.Errorf("SingletonProvider has no Provider.")
+		}
+
+		wrapped, err := ParseProvider(info, inner)
+		if err != nil {
+			return nil, err
+		}
+
+		wrapped.Singleton = true
+		return wrapped, nil
+
+	default:
+		return nil, fmt.Errorf("Cannot evaluate %s statically.", describeKind(kind, info.TypeOf(lit)))
+	}
+
+	return binding, nil
+}
+
+// parseBind evaluates a call to inject.Bind, which returns a transient
+// TypeProvider of impl bound as iface.
+func parseBind(info *types.Info, call *ast.CallExpr) (*Binding, error) {
+	iface, err := ReflectType(info, call.Args[1])
+	if err != nil {
+		return nil, fmt.Errorf("Cannot evaluate Bind's interface statically: %s", err)
+	}
+
+	impl, err := ReflectType(info, call.Args[2])
+	if err != nil {
+		return nil, fmt.Errorf("Cannot evaluate Bind's implementation statically: %s", err)
+	}
+
+	return &Binding{
+		Kind: "TypeProvider",
+		Pos:  call.Pos(),
+		Type: impl,
+		As:   []types.Type{iface},
+	}, nil
+}
+
+// ReflectType evaluates reflect.TypeOf(x) or reflect.TypeOf(x).Elem(), the
+// forms used to name types such as reflect.TypeOf((*Iface)(nil)).Elem().
+func ReflectType(info *types.Info, expr ast.Expr) (types.Type, error) {
+	if expr == nil {
+		return nil, fmt.Errorf("no type given")
+	}
+
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil, fmt.Errorf("expected reflect.TypeOf")
+	}
+
+	if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Elem" && len(call.Args) == 0 {
+		typ, err := ReflectType(info, selector.X)
+		if err != nil {
+			return nil, err
+		}
+
+		if elem, ok := typ.Underlying().(interface{ Elem() types.Type }); ok {
+			return elem.Elem(), nil
+		}
+
+		return nil, fmt.Errorf("%s has no element type", typ)
+	}
+
+	if !isFunc(info, call.Fun, "reflect", "TypeOf") || len(call.Args) != 1 {
+		return nil, fmt.Errorf("expected reflect.TypeOf")
+	}
+
+	return info.TypeOf(call.Args[0]), nil
+}
+
+func reflectTypes(info *types.Info, expr ast.Expr) ([]types.Type, error) {
+	lit, ok := ast.Unparen(expr).(*ast.CompositeLit)
+	if !ok {
+		return nil, fmt.Errorf("expected a []reflect.Type literal")
+	}
+
+	var typs []types.Type
+	for _, elt := range lit.Elts {
+		typ, err := ReflectType(info, elt)
+		if err != nil {
+			return nil, err
+		}
+		typs = append(typs, typ)
+	}
+
+	return typs, nil
+}
+
+func stringValue(info *types.Info, expr ast.Expr) (string, error) {
+	value := info.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", fmt.Errorf("expected a constant string")
+	}
+
+	return constant.StringVal(value), nil
+}
+
+func isFunc(info *types.Info, expr ast.Expr, pkg, name string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	fn, ok := info.Uses[selector.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == pkg && fn.Name() == name
+}
+
+// injectTypeName returns the name of a type, or of the type a pointer
+// refers to, if it is declared in the inject package.
+func injectTypeName(typ types.Type) string {
+	if typ == nil {
+		return ""
+	}
+
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != InjectPath {
+		return ""
+	}
+
+	return named.Obj().Name()
+}
+
+// injectObject looks up a name in the package declaring typ, which is one
+// of the inject package's types.
+func injectObject(typ types.Type, name string) types.Object {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+
+	return named.Obj().Pkg().Scope().Lookup(name)
+}
+
+// unconvert removes conversions to interface types from an expression.
+func unconvert(info *types.Info, expr ast.Expr) ast.Expr {
+	for {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok ||
+			len(call.Args) != 1 ||
+			!info.Types[call.Fun].IsType() ||
+			!types.IsInterface(info.TypeOf(call.Fun)) {
+			return expr
+		}
+
+		expr = call.Args[0]
+	}
+}
+
+func describeKind(kind string, typ types.Type) string {
+	if kind != "" {
+		return kind
+	}
+
+	return fmt.Sprint(typ)
+}
@@ -0,0 +1,67 @@
+package wiring
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+)
+
+// Dependency is a request a binding makes of the graph: an inject-tagged
+// field of the type it provides or of a struct builder parameter, or any
+// other builder parameter.
+type Dependency struct {
+	Path     string
+	Type     types.Type
+	Context  types.Type
+	Name     string
+	Optional bool
+	Pos      token.Pos
+}
+
+// Dependencies returns the requests a binding makes, in the order the graph
+// makes them.
+func Dependencies(binding *Binding) []Dependency {
+	var deps []Dependency
+
+	if binding.Signature != nil {
+		params := binding.Signature.Params()
+		for i := 0; i < params.Len(); i++ {
+			param := params.At(i)
+			if IsParamObject(param.Type()) || isStruct(param.Type()) {
+				deps = append(deps, FieldDependencies(param.Type())...)
+				continue
+			}
+
+			deps = append(deps, Dependency{
+				Path: fmt.Sprintf("arg %d", i),
+				Type: param.Type(),
+				Pos:  binding.Pos,
+			})
+		}
+	}
+
+	return append(deps, FieldDependencies(binding.Type)...)
+}
+
+// FieldDependencies returns the requests made to complete a struct, or the
+// struct a pointer refers to.
+func FieldDependencies(typ types.Type) []Dependency {
+	var deps []Dependency
+	for _, field := range Fields(typ) {
+		deps = append(deps, Dependency{
+			Path:     field.Path(),
+			Type:     field.Var.Type(),
+			Context:  field.Owner,
+			Name:     field.Name,
+			Optional: field.Optional,
+			Pos:      field.Pos(),
+		})
+	}
+
+	return deps
+}
+
+func isStruct(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Struct)
+	return ok
+}
@@ -0,0 +1,122 @@
+package wiring
+
+import (
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+)
+
+// Field is an inject-tagged field of a struct.
+type Field struct {
+	// Owner is the struct type declaring the field, which is also the
+	// context in which it is looked up.
+	Owner    types.Type
+	Var      *types.Var
+	Name     string
+	Optional bool
+}
+
+func (f Field) Pos() token.Pos {
+	return f.Var.Pos()
+}
+
+// Path names the field as Owner.Field.
+func (f Field) Path() string {
+	return typeName(f.Owner) + "." + f.Var.Name()
+}
+
+// Fields returns the inject-tagged fields of a struct, or of the struct a
+// pointer refers to.
+func Fields(typ types.Type) []Field {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	var fields []Field
+	for i := 0; i < st.NumFields(); i++ {
+		tag, ok := LookupTag(st.Tag(i))
+		if !ok {
+			continue
+		}
+
+		name, optional := ParseTag(tag)
+		fields = append(fields, Field{
+			Owner:    typ,
+			Var:      st.Field(i),
+			Name:     name,
+			Optional: optional,
+		})
+	}
+
+	return fields
+}
+
+var tagPattern = regexp.MustCompile(`(.+?):"(.*?)"`)
+
+// LookupTag finds the inject tag the way the graph does: only the first
+// space-separated key:"value" pair of a struct tag is considered.
+func LookupTag(tag string) (string, bool) {
+	for _, part := range strings.Split(tag, " ") {
+		if matches := tagPattern.FindStringSubmatch(part); len(matches) == 3 {
+			return matches[2], matches[1] == "inject"
+		}
+	}
+
+	return "", false
+}
+
+// ParseTag splits an inject tag into its name and whether it is optional.
+func ParseTag(tag string) (string, bool) {
+	parts := strings.Split(tag, ",")
+
+	optional := false
+	for _, option := range parts[1:] {
+		if strings.TrimSpace(option) == "optional" {
+			optional = true
+		}
+	}
+
+	return parts[0], optional
+}
+
+// IsParamObject reports whether a type is a struct embedding inject.In.
+func IsParamObject(typ types.Type) bool {
+	return embeds(typ, "In")
+}
+
+// IsResultObject reports whether a type is a struct embedding inject.Out.
+func IsResultObject(typ types.Type) bool {
+	return embeds(typ, "Out")
+}
+
+func embeds(typ types.Type, marker string) bool {
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if field.Anonymous() && injectTypeName(field.Type()) == marker {
+			if _, ok := field.Type().(*types.Pointer); !ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func typeName(typ types.Type) string {
+	if named, ok := typ.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+
+	return typ.String()
+}
@@ -0,0 +1,109 @@
+package wiring
+
+import (
+	"go/types"
+	"strings"
+)
+
+// Select returns the bindings which could satisfy a request, narrowed by
+// type, then context, then name, as the graph's findHelper does. A request
+// is satisfied when exactly one binding remains.
+func Select(bindings []*Binding, typ, context types.Type, name string) []*Binding {
+	var byType []*Binding
+	for _, binding := range bindings {
+		if providesType(binding, typ) {
+			byType = append(byType, binding)
+		}
+	}
+
+	var byContext []*Binding
+	if context == nil {
+		byContext = byType
+	} else {
+		var contextMatches, noContexts []*Binding
+		for _, binding := range byType {
+			switch {
+			case binding.Context == nil:
+				noContexts = append(noContexts, binding)
+
+			case types.ConvertibleTo(context, binding.Context) ||
+				types.ConvertibleTo(types.NewPointer(context), binding.Context):
+				contextMatches = append(contextMatches, binding)
+			}
+		}
+
+		byContext = contextMatches
+		if len(contextMatches) == 0 {
+			byContext = noContexts
+		}
+	}
+
+	var byName []*Binding
+	for _, binding := range byContext {
+		if strings.EqualFold(binding.Name, name) {
+			byName = append(byName, binding)
+		}
+	}
+
+	return byName
+}
+
+func providesType(binding *Binding, typ types.Type) bool {
+	if len(binding.As) > 0 {
+		for _, as := range binding.As {
+			if types.AssignableTo(as, typ) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return types.AssignableTo(binding.Type, typ)
+}
+
+// CanOverride reports whether an override stands in for a binding, as the
+// graph's Override decides: both have the same name and context and are
+// bound as the same set of types.
+func CanOverride(override, binding *Binding) bool {
+	if !strings.EqualFold(override.Name, binding.Name) ||
+		(override.Context == nil) != (binding.Context == nil) ||
+		(override.Context != nil && !types.Identical(override.Context, binding.Context)) {
+		return false
+	}
+
+	return sameTypes(BoundTypes(override), BoundTypes(binding))
+}
+
+// BoundTypes returns the types a binding was declared as or, if it did not
+// declare any, its own type.
+func BoundTypes(binding *Binding) []types.Type {
+	if len(binding.As) > 0 {
+		return binding.As
+	}
+
+	return []types.Type{binding.Type}
+}
+
+// sameTypes reports whether two lists hold the same set of types.
+func sameTypes(a, b []types.Type) bool {
+	return containsTypes(a, b) && containsTypes(b, a)
+}
+
+func containsTypes(a, b []types.Type) bool {
+	for _, typ := range b {
+		found := false
+		for _, other := range a {
+			if types.Identical(typ, other) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -6,6 +6,7 @@ package mock_inject
 import (
 	gomock "github.com/golang/mock/gomock"
 	inject "github.com/impinj/go-inject/inject"
+	io "io"
 	reflect "reflect"
 )
 
@@ -44,6 +45,18 @@ func (_mr *MockGraphMockRecorder) Complete(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Complete", arg0)
 }
 
+// Describe mocks base method
+func (_m *MockGraph) Describe() inject.Description {
+	ret := _m.ctrl.Call(_m, "Describe")
+	ret0, _ := ret[0].(inject.Description)
+	return ret0
+}
+
+// Describe indicates an expected call of Describe
+func (_mr *MockGraphMockRecorder) Describe() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Describe")
+}
+
 // Find mocks base method
 func (_m *MockGraph) Find(_param0 reflect.Type, _param1 reflect.Type, _param2 string) (interface{}, error) {
 	ret := _m.ctrl.Call(_m, "Find", _param0, _param1, _param2)
@@ -57,6 +70,47 @@ func (_mr *MockGraphMockRecorder) Find(arg0, arg1, arg2 interface{}) *gomock.Cal
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Find", arg0, arg1, arg2)
 }
 
+// InjectionPoints mocks base method
+func (_m *MockGraph) InjectionPoints() []inject.InjectionPoint {
+	ret := _m.ctrl.Call(_m, "InjectionPoints")
+	ret0, _ := ret[0].([]inject.InjectionPoint)
+	return ret0
+}
+
+// InjectionPoints indicates an expected call of InjectionPoints
+func (_mr *MockGraphMockRecorder) InjectionPoints() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InjectionPoints")
+}
+
+// Origin mocks base method
+func (_m *MockGraph) Origin(_param0 reflect.Type, _param1 reflect.Type, _param2 string) (string, bool) {
+	ret := _m.ctrl.Call(_m, "Origin", _param0, _param1, _param2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Origin indicates an expected call of Origin
+func (_mr *MockGraphMockRecorder) Origin(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Origin", arg0, arg1, arg2)
+}
+
+// Override mocks base method
+func (_m *MockGraph) Override(_param0 ...inject.Provider) error {
+	_s := []interface{}{}
+	for _, _x := range _param0 {
+		_s = append(_s, _x)
+	}
+	ret := _m.ctrl.Call(_m, "Override", _s...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Override indicates an expected call of Override
+func (_mr *MockGraphMockRecorder) Override(arg0 ...interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Override", arg0...)
+}
+
 // Provide mocks base method
 func (_m *MockGraph) Provide(_param0 ...inject.Provider) {
 	_s := []interface{}{}
@@ -71,6 +125,18 @@ func (_mr *MockGraphMockRecorder) Provide(arg0 ...interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Provide", arg0...)
 }
 
+// Provides mocks base method
+func (_m *MockGraph) Provides(_param0 reflect.Type, _param1 reflect.Type, _param2 string) bool {
+	ret := _m.ctrl.Call(_m, "Provides", _param0, _param1, _param2)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Provides indicates an expected call of Provides
+func (_mr *MockGraphMockRecorder) Provides(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Provides", arg0, arg1, arg2)
+}
+
 // Resolve mocks base method
 func (_m *MockGraph) Resolve() error {
 	ret := _m.ctrl.Call(_m, "Resolve")
@@ -82,3 +148,15 @@ func (_m *MockGraph) Resolve() error {
 func (_mr *MockGraphMockRecorder) Resolve() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Resolve")
 }
+
+// WriteDot mocks base method
+func (_m *MockGraph) WriteDot(_param0 io.Writer) error {
+	ret := _m.ctrl.Call(_m, "WriteDot", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteDot indicates an expected call of WriteDot
+func (_mr *MockGraphMockRecorder) WriteDot(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "WriteDot", arg0)
+}